@@ -41,45 +41,72 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"html/template"
 	"io"
 	stdLog "log"
 	"net"
 	"net/http"
+	"os"
 	"path"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/itchenyi/common/color"
 	"github.com/itchenyi/common/log"
 	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 type (
 	// Akita is the top-level framework instance.
 	Akita struct {
-		stdLogger        *stdLog.Logger
-		colorer          *color.Color
-		premiddleware    []MiddlewareFunc
-		middleware       []MiddlewareFunc
-		maxParam         *int
-		router           *Router
-		notFoundHandler  HandlerFunc
-		pool             sync.Pool
-		Server           *http.Server
-		TLSServer        *http.Server
-		Listener         net.Listener
-		TLSListener      net.Listener
-		AutoTLSManager   autocert.Manager
-		DisableHTTP2     bool
-		Debug            bool
-		HideBanner       bool
-		HTTPErrorHandler HTTPErrorHandler
-		Binder           Binder
-		Validator        Validator
-		Renderer         Renderer
+		stdLogger               *stdLog.Logger
+		colorer                 *color.Color
+		premiddleware           []MiddlewareFunc
+		middleware              []MiddlewareFunc
+		maxParam                *int
+		routeSeq                int
+		router                  *Router
+		routers                 map[string]*Router
+		notFoundHandler         HandlerFunc
+		methodNotAllowedHandler HandlerFunc
+		drainHandler            HandlerFunc
+		draining                int32
+		stats                   *stats
+		pool                    sync.Pool
+		serversMutex            sync.Mutex
+		servers                 []*http.Server
+		Server                  *http.Server
+		TLSServer               *http.Server
+		Listener                net.Listener
+		TLSListener             net.Listener
+		AutoTLSManager          autocert.Manager
+		DisableHTTP2            bool
+		Debug                   bool
+		Banner                  string
+		HideBanner              bool
+		HidePort                bool
+		LogStartup              bool
+		JSONPrettyQuery         bool
+		JSONEscapeHTML          bool
+		ServerHeader            string
+		HTTPErrorHandler        HTTPErrorHandler
+		IPExtractor             IPExtractor
+		MIMETypes               map[string]string
+		MaxMultipartMemory      int64
+		MaxHeaderBytes          int
+		MaxURILength            int
+		NormalizeCharset        bool
+		Binder                  Binder
+		Validator               Validator
+		Renderer                Renderer
 		// Mutex            sync.RWMutex
 		Logger Logger
 	}
@@ -89,6 +116,12 @@ type (
 		Method string `json:"method"`
 		Path   string `json:"path"`
 		Name   string `json:"name"`
+		Host   string `json:"host,omitempty"`
+		Data   Map    `json:"-"`
+		// Middleware is the group and route-level middleware of the route, in
+		// the order they run after the global one.
+		Middleware []MiddlewareFunc `json:"-"`
+		seq        int
 	}
 
 	// HTTPError represents an error that occurred while handling a request.
@@ -145,6 +178,8 @@ const (
 	MIMEApplicationJSONCharsetUTF8       = MIMEApplicationJSON + "; " + charsetUTF8
 	MIMEApplicationJavaScript            = "application/javascript"
 	MIMEApplicationJavaScriptCharsetUTF8 = MIMEApplicationJavaScript + "; " + charsetUTF8
+	MIMEApplicationProblemJSON           = "application/problem+json"
+	MIMEApplicationNDJSON                = "application/x-ndjson"
 	MIMEApplicationXML                   = "application/xml"
 	MIMEApplicationXMLCharsetUTF8        = MIMEApplicationXML + "; " + charsetUTF8
 	MIMETextXML                          = "text/xml"
@@ -157,6 +192,7 @@ const (
 	MIMETextPlain                        = "text/plain"
 	MIMETextPlainCharsetUTF8             = MIMETextPlain + "; " + charsetUTF8
 	MIMEMultipartForm                    = "multipart/form-data"
+	MIMEMultipartMixed                   = "multipart/mixed"
 	MIMEOctetStream                      = "application/octet-stream"
 )
 
@@ -168,21 +204,26 @@ const (
 const (
 	HeaderAccept              = "Accept"
 	HeaderAcceptEncoding      = "Accept-Encoding"
+	HeaderAcceptLanguage      = "Accept-Language"
 	HeaderAllow               = "Allow"
 	HeaderAuthorization       = "Authorization"
+	HeaderCacheControl        = "Cache-Control"
 	HeaderContentDisposition  = "Content-Disposition"
 	HeaderContentEncoding     = "Content-Encoding"
 	HeaderContentLength       = "Content-Length"
 	HeaderContentType         = "Content-Type"
 	HeaderCookie              = "Cookie"
+	HeaderETag                = "ETag"
 	HeaderSetCookie           = "Set-Cookie"
 	HeaderIfModifiedSince     = "If-Modified-Since"
+	HeaderIfNoneMatch         = "If-None-Match"
 	HeaderLastModified        = "Last-Modified"
 	HeaderLocation            = "Location"
 	HeaderUpgrade             = "Upgrade"
 	HeaderVary                = "Vary"
 	HeaderWWWAuthenticate     = "WWW-Authenticate"
 	HeaderXForwardedFor       = "X-Forwarded-For"
+	HeaderXForwardedHost      = "X-Forwarded-Host"
 	HeaderXForwardedProto     = "X-Forwarded-Proto"
 	HeaderXForwardedProtocol  = "X-Forwarded-Protocol"
 	HeaderXForwardedSsl       = "X-Forwarded-Ssl"
@@ -192,6 +233,8 @@ const (
 	HeaderXRequestID          = "X-Request-ID"
 	HeaderServer              = "Server"
 	HeaderOrigin              = "Origin"
+	HeaderRetryAfter          = "Retry-After"
+	HeaderServerTiming        = "Server-Timing"
 
 	// Access control
 	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
@@ -250,13 +293,30 @@ var (
 	ErrForbidden                   = NewHTTPError(http.StatusForbidden)
 	ErrMethodNotAllowed            = NewHTTPError(http.StatusMethodNotAllowed)
 	ErrStatusRequestEntityTooLarge = NewHTTPError(http.StatusRequestEntityTooLarge)
+	ErrURITooLong                  = NewHTTPError(http.StatusRequestURITooLong)
+	ErrServiceUnavailable          = NewHTTPError(http.StatusServiceUnavailable)
+	ErrClientCertificateRequired   = NewHTTPError(http.StatusUnauthorized, "Client certificate required")
 	ErrValidatorNotRegistered      = errors.New("Validator not registered")
 	ErrRendererNotRegistered       = errors.New("Renderer not registered")
 	ErrInvalidRedirectCode         = errors.New("Invalid redirect status code")
+	ErrRouteNotFound               = errors.New("Route not found")
 	ErrCookieNotFound              = errors.New("Cookie not found")
+	ErrInvalidCookieSignature      = errors.New("Invalid cookie signature")
+	ErrStreamCanceled              = errors.New("Stream canceled")
 )
 
-// Error handlers
+// Generic error page used by `HTMLErrorHandler()` for unmapped status codes.
+var errorPage = template.Must(template.New("error").Parse(`<!doctype html>
+<html>
+<head><title>{{.code}}</title></head>
+<body><h1>{{.code}}</h1><p>{{.message}}</p></body>
+</html>
+`))
+
+// Error handlers. They are the defaults copied into every instance created by
+// `New()`, use `Akita#SetNotFoundHandler()`,
+// `Akita#SetMethodNotAllowedHandler()` and `Akita#SetDrainHandler()` to
+// customize them per instance.
 var (
 	NotFoundHandler = func(c Context) error {
 		return ErrNotFound
@@ -265,6 +325,11 @@ var (
 	MethodNotAllowedHandler = func(c Context) error {
 		return ErrMethodNotAllowed
 	}
+
+	DrainHandler = func(c Context) error {
+		c.Response().Header().Set(HeaderRetryAfter, "5")
+		return ErrServiceUnavailable
+	}
 )
 
 // New creates an instance of Akita.
@@ -275,9 +340,13 @@ func New() (a *Akita) {
 		AutoTLSManager: autocert.Manager{
 			Prompt: autocert.AcceptTOS,
 		},
-		Logger:   log.New("akita"),
-		colorer:  color.New(),
-		maxParam: new(int),
+		Logger:                  log.New("akita"),
+		colorer:                 color.New(),
+		maxParam:                new(int),
+		notFoundHandler:         NotFoundHandler,
+		methodNotAllowedHandler: MethodNotAllowedHandler,
+		drainHandler:            DrainHandler,
+		stats:                   &stats{start: time.Now()},
 	}
 	a.Server.Handler = a
 	a.TLSServer.Handler = a
@@ -289,6 +358,13 @@ func New() (a *Akita) {
 		return a.NewContext(nil, nil)
 	}
 	a.router = NewRouter(a)
+	a.routers = map[string]*Router{}
+	a.MaxMultipartMemory = defaultMemory
+	a.JSONEscapeHTML = true
+	a.MIMETypes = map[string]string{
+		".wasm":        "application/wasm",
+		".webmanifest": "application/manifest+json",
+	}
 	return
 }
 
@@ -300,15 +376,38 @@ func (a *Akita) NewContext(r *http.Request, w http.ResponseWriter) Context {
 		store:    make(Map),
 		akita:    a,
 		pvalues:  make([]string, *a.maxParam),
-		handler:  NotFoundHandler,
+		handler:  a.notFoundHandler,
 	}
 }
 
-// Router returns router.
+// SetNotFoundHandler sets the handler invoked when no route matches the
+// request path.
+func (a *Akita) SetNotFoundHandler(h HandlerFunc) {
+	a.notFoundHandler = h
+}
+
+// SetMethodNotAllowedHandler sets the handler invoked when a route matches
+// the request path but not the request method.
+func (a *Akita) SetMethodNotAllowedHandler(h HandlerFunc) {
+	a.methodNotAllowedHandler = h
+}
+
+// SetDrainHandler sets the handler invoked for the requests arriving once
+// `Akita#Shutdown()` has been called, instead of routing them.
+func (a *Akita) SetDrainHandler(h HandlerFunc) {
+	a.drainHandler = h
+}
+
+// Router returns the default router.
 func (a *Akita) Router() *Router {
 	return a.router
 }
 
+// Routers returns the routers registered per host with `Akita#Host()`.
+func (a *Akita) Routers() map[string]*Router {
+	return a.routers
+}
+
 // DefaultHTTPErrorHandler is the default HTTP error handler. It sends a JSON response
 // with status code.
 func (a *Akita) DefaultHTTPErrorHandler(err error, ctx Context) {
@@ -320,6 +419,11 @@ func (a *Akita) DefaultHTTPErrorHandler(err error, ctx Context) {
 	if he, ok := err.(*HTTPError); ok {
 		code = he.Code
 		msg = he.Message
+	} else if p, ok := err.(*Problem); ok {
+		if p.Status != 0 {
+			code = p.Status
+		}
+		msg = p
 	} else if a.Debug {
 		msg = err.Error()
 		if he.Inner != nil {
@@ -328,16 +432,21 @@ func (a *Akita) DefaultHTTPErrorHandler(err error, ctx Context) {
 	} else {
 		msg = http.StatusText(code)
 	}
-	if _, ok := msg.(string); ok {
-		msg = Map{"message": msg}
+	switch m := msg.(type) {
+	case string:
+		msg = Map{"message": m}
+	case BindErrors:
+		msg = Map{"message": "Invalid request data", "errors": m}
 	}
 
-	a.Logger.Error(err)
+	a.logError(ctx, err)
 
 	// Send response
 	if !ctx.Response().Committed {
 		if ctx.Request().Method == HEAD { // Issue #608
 			err = ctx.NoContent(code)
+		} else if p, ok := msg.(*Problem); ok {
+			err = ctx.Problem(code, *p)
 		} else {
 			err = ctx.JSON(code, msg)
 		}
@@ -347,6 +456,119 @@ func (a *Akita) DefaultHTTPErrorHandler(err error, ctx Context) {
 	}
 }
 
+// HTMLErrorHandler returns an HTTP error handler which renders an HTML error page
+// for browser clients, detected via `Accept: text/html`, and falls back to
+// `Akita#DefaultHTTPErrorHandler()` otherwise. The templates are keyed by status
+// code and executed with the `code` and `message` of the error. Status codes
+// without a template are rendered using a generic one.
+func HTMLErrorHandler(templates map[int]string) HTTPErrorHandler {
+	pages := make(map[int]*template.Template, len(templates))
+	for code, t := range templates {
+		pages[code] = template.Must(template.New(strconv.Itoa(code)).Parse(t))
+	}
+
+	return func(err error, ctx Context) {
+		a := ctx.Akita()
+		if !acceptsHTML(ctx) {
+			a.DefaultHTTPErrorHandler(err, ctx)
+			return
+		}
+
+		code, msg := a.htmlError(err)
+		a.logError(ctx, err)
+
+		// Send response
+		if ctx.Response().Committed {
+			return
+		}
+		if ctx.Request().Method == HEAD {
+			ctx.NoContent(code)
+			return
+		}
+		page, ok := pages[code]
+		if !ok {
+			page = errorPage
+		}
+		buf := new(bytes.Buffer)
+		if err = page.Execute(buf, Map{"code": code, "message": msg}); err == nil {
+			err = ctx.HTMLBlob(code, buf.Bytes())
+		}
+		if err != nil {
+			a.Logger.Error(err)
+		}
+	}
+}
+
+// RenderErrorHandler returns an HTTP error handler which renders the error page
+// for browser clients, detected via `Accept: text/html`, with the template name
+// of the registered `Renderer`, "error.html" if empty. The template is executed
+// with the `code` and `message` of the error. Other clients, or all of them if
+// no renderer is registered or the template can't be rendered, are handled by
+// `Akita#DefaultHTTPErrorHandler()`.
+func RenderErrorHandler(name string) HTTPErrorHandler {
+	if name == "" {
+		name = "error.html"
+	}
+
+	return func(err error, ctx Context) {
+		a := ctx.Akita()
+		if a.Renderer == nil || !acceptsHTML(ctx) || ctx.Request().Method == HEAD {
+			a.DefaultHTTPErrorHandler(err, ctx)
+			return
+		}
+
+		code, msg := a.htmlError(err)
+		html, rerr := ctx.RenderToString(name, Map{"code": code, "message": msg})
+		if rerr != nil {
+			a.Logger.Error(rerr)
+			a.DefaultHTTPErrorHandler(err, ctx)
+			return
+		}
+
+		a.logError(ctx, err)
+
+		// Send response
+		if !ctx.Response().Committed {
+			if err = ctx.HTML(code, html); err != nil {
+				a.Logger.Error(err)
+			}
+		}
+	}
+}
+
+// logError logs err handled by an HTTP error handler, along with the ID of the
+// request if any, the same as in the access log of the Logger middleware.
+func (a *Akita) logError(ctx Context, err error) {
+	id := ctx.Request().Header.Get(HeaderXRequestID)
+	if id == "" {
+		id = ctx.Response().Header().Get(HeaderXRequestID)
+	}
+	if id == "" {
+		a.Logger.Error(err)
+		return
+	}
+	a.Logger.Errorf("id=%s: %v", id, err)
+}
+
+// acceptsHTML reports whether the client of the request accepts HTML.
+func acceptsHTML(ctx Context) bool {
+	return strings.Contains(ctx.Request().Header.Get(HeaderAccept), MIMETextHTML)
+}
+
+// htmlError returns the status code and message of err shown on HTML error
+// pages.
+func (a *Akita) htmlError(err error) (code int, msg string) {
+	code = http.StatusInternalServerError
+	msg = http.StatusText(code)
+	if he, ok := err.(*HTTPError); ok {
+		code = he.Code
+		msg = fmt.Sprintf("%v", he.Message)
+	} else if a.Debug {
+		msg = err.Error()
+	}
+	return
+}
+
 // Pre adds middleware to the chain which is run before router.
 func (a *Akita) Pre(middleware ...MiddlewareFunc) {
 	a.premiddleware = append(a.premiddleware, middleware...)
@@ -357,6 +579,19 @@ func (a *Akita) Use(middleware ...MiddlewareFunc) {
 	a.middleware = append(a.middleware, middleware...)
 }
 
+// ResetPreMiddleware removes all middleware added with `Pre()`. It must not be
+// called while the server is handling requests.
+func (a *Akita) ResetPreMiddleware() {
+	a.premiddleware = nil
+}
+
+// ResetMiddleware removes all middleware added with `Use()`. Route and group
+// level middleware are not affected. It must not be called while the server
+// is handling requests.
+func (a *Akita) ResetMiddleware() {
+	a.middleware = nil
+}
+
 // CONNECT registers a new CONNECT route for a path with matching handler in the
 // router with optional route-level middleware.
 func (a *Akita) CONNECT(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
@@ -421,6 +656,23 @@ func (a *Akita) Any(path string, handler HandlerFunc, middleware ...MiddlewareFu
 	return routes
 }
 
+// AddPaths registers the handler for an HTTP method under each of the paths,
+// e.g. for aliases, with optional route-level middleware. `Akita#Reverse()`
+// and `Akita#URI()` resolve to the first path.
+func (a *Akita) AddPaths(method string, paths []string, handler HandlerFunc, middleware ...MiddlewareFunc) []*Route {
+	routes := make([]*Route, 0, len(paths))
+	for _, p := range paths {
+		routes = append(routes, a.Add(method, p, handler, middleware...))
+	}
+	return routes
+}
+
+// Except registers a new route for all HTTP methods but the excluded ones and
+// path with matching handler in the router with optional route-level middleware.
+func (a *Akita) Except(excluded []string, path string, handler HandlerFunc, middleware ...MiddlewareFunc) []*Route {
+	return a.Match(exceptMethods(excluded), path, handler, middleware...)
+}
+
 // Match registers a new route for multiple HTTP methods and path with matching
 // handler in the router with optional route-level middleware.
 func (a *Akita) Match(methods []string, path string, handler HandlerFunc, middleware ...MiddlewareFunc) []*Route {
@@ -467,21 +719,42 @@ func (a *Akita) File(path, file string) *Route {
 // Add registers a new route for an HTTP method and path with matching handler
 // in the router with optional route-level middleware.
 func (a *Akita) Add(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
-	name := handlerName(handler)
-	a.router.Add(method, path, func(ctx Context) error {
-		h := handler
-		// Chain middleware
-		for i := len(middleware) - 1; i >= 0; i-- {
-			h = middleware[i](h)
+	return a.add("", method, path, handler, middleware...)
+}
+
+func (a *Akita) add(host, method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
+	router := a.router
+	if host != "" {
+		if router = a.routers[host]; router == nil {
+			router = NewRouter(a)
+			a.routers[host] = router
 		}
-		return h(ctx)
-	})
+	}
+	router.Add(method, path, handler)
+	// Keyed by the path as matched by the router, see `Context#Path()`
+	if path[0] != '/' {
+		path = "/" + path
+	}
+	a.routeSeq++
 	r := &Route{
-		Method: method,
-		Path:   path,
-		Name:   name,
+		Method:     method,
+		Path:       path,
+		Name:       handlerName(handler),
+		Host:       host,
+		Middleware: middleware,
+		seq:        a.routeSeq,
 	}
-	a.router.routes[method+path] = r
+	router.routes[method+path] = r
+	return r
+}
+
+// SetData stores a value in the data of the route, e.g. to configure
+// middleware for this route only. It returns the route for chaining.
+func (r *Route) SetData(key string, val interface{}) *Route {
+	if r.Data == nil {
+		r.Data = Map{}
+	}
+	r.Data[key] = val
 	return r
 }
 
@@ -492,6 +765,15 @@ func (a *Akita) Group(prefix string, m ...MiddlewareFunc) (g *Group) {
 	return
 }
 
+// Host creates a new router group for the provided host and optional host-level
+// middleware. Requests are matched against the routes of their `Host` header
+// first, falling back to the default router if no routes are registered for it.
+func (a *Akita) Host(name string, m ...MiddlewareFunc) (g *Group) {
+	g = &Group{host: name, akita: a}
+	g.Use(m...)
+	return
+}
+
 // URI generates a URI from handler.
 func (a *Akita) URI(handler HandlerFunc, params ...interface{}) string {
 	name := handlerName(handler)
@@ -503,31 +785,54 @@ func (a *Akita) URL(h HandlerFunc, params ...interface{}) string {
 	return a.URI(h, params...)
 }
 
-// Reverse generates an URL from route name and provided parameters.
+// Reverse generates an URL from route name and provided parameters. The
+// parameters are path escaped. A `*` wildcard takes all the remaining ones,
+// joined with slashes, the slashes in them being kept.
 func (a *Akita) Reverse(name string, params ...interface{}) string {
+	// The route registered first wins if several share the name, e.g. aliases
+	var route *Route
+	for _, r := range a.allRoutes() {
+		if r.Name == name && (route == nil || r.seq < route.seq) {
+			route = r
+		}
+	}
+	if route == nil {
+		return ""
+	}
+
 	uri := new(bytes.Buffer)
 	ln := len(params)
 	n := 0
-	for _, r := range a.router.routes {
-		if r.Name == name {
-			for i, l := 0, len(r.Path); i < l; i++ {
-				if r.Path[i] == ':' && n < ln {
-					for ; i < l && r.Path[i] != '/'; i++ {
-					}
-					uri.WriteString(fmt.Sprintf("%v", params[n]))
-					n++
+	for i, l := 0, len(route.Path); i < l; i++ {
+		if route.Path[i] == '\\' && i+1 < l && route.Path[i+1] == ':' {
+			i++
+		} else if route.Path[i] == ':' && n < ln {
+			for ; i < l && route.Path[i] != '/'; i++ {
+			}
+			uri.WriteString(pathEscape(fmt.Sprintf("%v", params[n])))
+			n++
+		} else if route.Path[i] == '*' && n < ln {
+			for ; n < ln; n++ {
+				segments := strings.Split(fmt.Sprintf("%v", params[n]), "/")
+				for j, s := range segments {
+					segments[j] = pathEscape(s)
 				}
-				if i < l {
-					uri.WriteByte(r.Path[i])
+				uri.WriteString(strings.Join(segments, "/"))
+				if n < ln-1 {
+					uri.WriteByte('/')
 				}
 			}
-			break
+			i++
+		}
+		if i < l {
+			uri.WriteByte(route.Path[i])
 		}
 	}
 	return uri.String()
 }
 
-// Routes returns the registered routes.
+// Routes returns the routes registered in the default router. Use
+// `Akita#Routers()` for the routes registered per host.
 func (a *Akita) Routes() []*Route {
 	routes := []*Route{}
 	for _, v := range a.router.routes {
@@ -536,6 +841,58 @@ func (a *Akita) Routes() []*Route {
 	return routes
 }
 
+// FindRoute returns the route registered for method and path, a route path such
+// as `/users/:id`, in the router of host, or nil if there is none. E.g. given
+// `Context#Path()`, it finds the route a preflight request is about.
+func (a *Akita) FindRoute(host, method, path string) *Route {
+	return a.findRouter(host).routes[method+path]
+}
+
+func (a *Akita) allRoutes() []*Route {
+	routes := a.Routes()
+	for _, router := range a.routers {
+		for _, v := range router.routes {
+			routes = append(routes, v)
+		}
+	}
+	return routes
+}
+
+// exceptMethods returns the HTTP methods not listed in excluded.
+func exceptMethods(excluded []string) []string {
+	ms := []string{}
+	for _, m := range methods {
+		found := false
+		for _, e := range excluded {
+			if strings.EqualFold(m, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// findRouter returns the router registered for the host, ignoring the port if
+// there is no exact match, or the default router.
+func (a *Akita) findRouter(host string) *Router {
+	if len(a.routers) == 0 {
+		return a.router
+	}
+	if router, ok := a.routers[host]; ok {
+		return router
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		if router, ok := a.routers[h]; ok {
+			return router
+		}
+	}
+	return a.router
+}
+
 // AcquireContext returns an empty `Context` instance from the pool.
 // You must return the context by calling `ReleaseContext()`.
 func (a *Akita) AcquireContext() Context {
@@ -558,6 +915,17 @@ func (a *Akita) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := a.pool.Get().(*context)
 	defer a.pool.Put(ctx)
 	ctx.Reset(r, w)
+	if a.ServerHeader != "" {
+		w.Header().Set(HeaderServer, a.ServerHeader)
+	}
+
+	// Draining, let the in-flight requests complete but turn new ones away
+	if atomic.LoadInt32(&a.draining) == 1 {
+		if err := a.drainHandler(ctx); err != nil {
+			ctx.Error(err)
+		}
+		return
+	}
 
 	// Middleware
 	h := func(ctx Context) error {
@@ -566,8 +934,17 @@ func (a *Akita) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		if urlPath == "" {
 			urlPath = r.URL.Path
 		}
-		a.router.Find(method, urlPath, ctx)
+		if a.MaxURILength > 0 && len(r.URL.RequestURI()) > a.MaxURILength {
+			return ErrURITooLong
+		}
+		router := a.findRouter(r.Host)
+		router.Find(method, urlPath, ctx)
 		h := ctx.Handler()
+		if route := router.routes[method+ctx.Path()]; route != nil {
+			for i := len(route.Middleware) - 1; i >= 0; i-- {
+				h = route.Middleware[i](h)
+			}
+		}
 		for i := len(a.middleware) - 1; i >= 0; i-- {
 			h = a.middleware[i](h)
 		}
@@ -581,7 +958,7 @@ func (a *Akita) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Execute chain
 	if err := h(ctx); err != nil {
-		a.HTTPErrorHandler(err, ctx)
+		ctx.Error(err)
 	}
 }
 
@@ -614,6 +991,19 @@ func (a *Akita) StartAutoTLS(address string) error {
 	return a.startTLS(address)
 }
 
+// AutoTLSHostWhitelist restricts the certificates `StartAutoTLS()` acquires to
+// the hosts, the requests for other server names failing before the CA is
+// contacted, which keeps random names from exhausting the rate limits.
+func (a *Akita) AutoTLSHostWhitelist(hosts ...string) {
+	a.AutoTLSManager.HostPolicy = autocert.HostWhitelist(hosts...)
+}
+
+// AutoTLSCacheDir makes `StartAutoTLS()` store the certificates in dir, so they
+// persist across restarts.
+func (a *Akita) AutoTLSCacheDir(dir string) {
+	a.AutoTLSManager.Cache = autocert.DirCache(dir)
+}
+
 func (a *Akita) startTLS(address string) error {
 	s := a.TLSServer
 	s.Addr = address
@@ -623,19 +1013,48 @@ func (a *Akita) startTLS(address string) error {
 	return a.StartServer(a.TLSServer)
 }
 
+// StartH2C starts an HTTP/2 server over cleartext (h2c), e.g. behind a proxy
+// speaking HTTP/2 without TLS. HTTP/1 requests are served as usual.
+// It requires `golang.org/x/net/http2`.
+func (a *Akita) StartH2C(address string, h2s *http2.Server) (err error) {
+	s := a.Server
+	s.Addr = address
+
+	// Setup
+	a.setupColorer()
+	s.ErrorLog = a.stdLogger
+	if a.MaxHeaderBytes != 0 {
+		s.MaxHeaderBytes = a.MaxHeaderBytes
+	}
+	s.Handler = h2c.NewHandler(a, h2s)
+	if a.Debug {
+		a.Logger.SetLevel(log.DEBUG)
+	}
+	a.printBanner()
+
+	if a.Listener == nil {
+		a.Listener, err = newListener(s.Addr)
+		if err != nil {
+			return err
+		}
+	}
+	a.printStarted("http", a.Listener.Addr())
+	return s.Serve(a.Listener)
+}
+
 // StartServer starts a custom http server.
 func (a *Akita) StartServer(s *http.Server) (err error) {
 	// Setup
-	a.colorer.SetOutput(a.Logger.Output())
+	a.setupColorer()
 	s.ErrorLog = a.stdLogger
+	if a.MaxHeaderBytes != 0 {
+		s.MaxHeaderBytes = a.MaxHeaderBytes
+	}
 	s.Handler = a
 	if a.Debug {
 		a.Logger.SetLevel(log.DEBUG)
 	}
-
-	if !a.HideBanner {
-		a.colorer.Printf(banner, a.colorer.Red("v"+version), a.colorer.Blue(website))
-	}
+	a.printBanner()
 
 	if s.TLSConfig == nil {
 		if a.Listener == nil {
@@ -644,9 +1063,7 @@ func (a *Akita) StartServer(s *http.Server) (err error) {
 				return err
 			}
 		}
-		if !a.HideBanner {
-			a.colorer.Printf("⇨ http server started on %s\n", a.colorer.Green(a.Listener.Addr()))
-		}
+		a.printStarted("http", a.Listener.Addr())
 		return s.Serve(a.Listener)
 	}
 	if a.TLSListener == nil {
@@ -656,12 +1073,64 @@ func (a *Akita) StartServer(s *http.Server) (err error) {
 		}
 		a.TLSListener = tls.NewListener(l, s.TLSConfig)
 	}
-	if !a.HideBanner {
-		a.colorer.Printf("⇨ https server started on %s\n", a.colorer.Green(a.TLSListener.Addr()))
-	}
+	a.printStarted("https", a.TLSListener.Addr())
 	return s.Serve(a.TLSListener)
 }
 
+// setupColorer directs the startup messages to the logger output. Colors are
+// disabled unless the output is a terminal.
+func (a *Akita) setupColorer() {
+	w := a.Logger.Output()
+	a.colorer.SetOutput(w)
+	if !isTerminal(w) {
+		a.colorer.Disable()
+	}
+}
+
+// printBanner prints the banner, `Banner` when set, unless `HideBanner` is set.
+// With `LogStartup` it is logged as an info message instead.
+func (a *Akita) printBanner() {
+	if a.HideBanner {
+		return
+	}
+	if a.Banner != "" {
+		if a.LogStartup {
+			a.Logger.Info(strings.TrimSpace(a.Banner))
+			return
+		}
+		a.colorer.Println(a.Banner)
+		return
+	}
+	if a.LogStartup {
+		a.Logger.Infof("akita v%s", version)
+		return
+	}
+	a.colorer.Printf(banner, a.colorer.Red("v"+version), a.colorer.Blue(website))
+}
+
+// printStarted prints the address the server listens on unless `HideBanner` or
+// `HidePort` is set. With `LogStartup` it is logged as an info message instead.
+func (a *Akita) printStarted(scheme string, addr net.Addr) {
+	if a.HideBanner || a.HidePort {
+		return
+	}
+	if a.LogStartup {
+		a.Logger.Infof("%s server started on %s", scheme, addr)
+		return
+	}
+	a.colorer.Printf("⇨ %s server started on %s\n", scheme, a.colorer.Green(addr))
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
 // NewHTTPError creates a new HTTPError instance.
 func NewHTTPError(code int, message ...interface{}) *HTTPError {
 	he := &HTTPError{Code: code, Message: http.StatusText(code)}
@@ -684,6 +1153,23 @@ func WrapHandler(h http.Handler) HandlerFunc {
 	}
 }
 
+// JSONHandler converts a handler returning the value to respond with into
+// `akita.HandlerFunc`. The value is sent as JSON with status 200, or status 204
+// if it is nil, unless the handler has responded already. Errors are returned
+// as is to the HTTPErrorHandler.
+func JSONHandler(h func(Context) (interface{}, error)) HandlerFunc {
+	return func(ctx Context) error {
+		v, err := h(ctx)
+		if err != nil || ctx.Response().Committed {
+			return err
+		}
+		if v == nil {
+			return ctx.NoContent(http.StatusNoContent)
+		}
+		return ctx.JSON(http.StatusOK, v)
+	}
+}
+
 // WrapMiddleware wraps `func(http.Handler) http.Handler` into `akita.MiddlewareFunc`
 func WrapMiddleware(m func(http.Handler) http.Handler) MiddlewareFunc {
 	return func(next HandlerFunc) HandlerFunc {
@@ -4,22 +4,114 @@ package akita
 
 import (
 	stdContext "context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/itchenyi/common/log"
 )
 
+// StartMulti starts HTTP servers listening on each of the addresses, e.g. a
+// public and an admin port, serving the same routes. The first one is
+// `Akita#Server`, the others get its timeouts and header limit. It returns
+// once one of them stops, closing the others unless they are shutting down
+// with `Akita#Shutdown()`, which as `Akita#Close()` applies to all of them.
+func (a *Akita) StartMulti(addresses ...string) error {
+	if len(addresses) == 0 {
+		return errors.New("no address to listen on")
+	}
+	listeners := make([]net.Listener, 0, len(addresses))
+	for _, address := range addresses {
+		l, err := newListener(address)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, l)
+	}
+
+	// Setup
+	a.setupColorer()
+	if a.Debug {
+		a.Logger.SetLevel(log.DEBUG)
+	}
+	a.printBanner()
+
+	servers := []*http.Server{a.Server}
+	a.serversMutex.Lock()
+	for range addresses[1:] {
+		s := &http.Server{
+			ReadTimeout:       a.Server.ReadTimeout,
+			ReadHeaderTimeout: a.Server.ReadHeaderTimeout,
+			WriteTimeout:      a.Server.WriteTimeout,
+			IdleTimeout:       a.Server.IdleTimeout,
+			MaxHeaderBytes:    a.Server.MaxHeaderBytes,
+		}
+		servers = append(servers, s)
+		a.servers = append(a.servers, s)
+	}
+	a.Listener = listeners[0]
+	a.serversMutex.Unlock()
+
+	errCh := make(chan error, len(servers))
+	for i, s := range servers {
+		s.Addr = addresses[i]
+		s.ErrorLog = a.stdLogger
+		if a.MaxHeaderBytes != 0 {
+			s.MaxHeaderBytes = a.MaxHeaderBytes
+		}
+		s.Handler = a
+		a.printStarted("http", listeners[i].Addr())
+		go func(s *http.Server, l net.Listener) {
+			errCh <- s.Serve(l)
+		}(s, listeners[i])
+	}
+	err := <-errCh
+	if err != http.ErrServerClosed {
+		for _, s := range servers {
+			s.Close()
+		}
+	}
+	return err
+}
+
 // Close immediately stops the server.
 // It internally calls `http.Server#Close()`.
 func (a *Akita) Close() error {
 	if err := a.TLSServer.Close(); err != nil {
 		return err
 	}
+	for _, s := range a.extraServers() {
+		if err := s.Close(); err != nil {
+			return err
+		}
+	}
 	return a.Server.Close()
 }
 
 // Shutdown stops server the gracefully.
-// It internally calls `http.Server#Shutdown()`.
+// It internally calls `http.Server#Shutdown()`. Requests arriving meanwhile
+// are answered by the drain handler, see `Akita#SetDrainHandler()`.
 func (a *Akita) Shutdown(ctx stdContext.Context) error {
+	atomic.StoreInt32(&a.draining, 1)
 	if err := a.TLSServer.Shutdown(ctx); err != nil {
 		return err
 	}
+	for _, s := range a.extraServers() {
+		if err := s.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
 	return a.Server.Shutdown(ctx)
 }
+
+// extraServers returns the servers started by `Akita#StartMulti()` besides
+// `Akita#Server`.
+func (a *Akita) extraServers() []*http.Server {
+	a.serversMutex.Lock()
+	defer a.serversMutex.Unlock()
+	return append([]*http.Server(nil), a.servers...)
+}
@@ -3,6 +3,11 @@
 package akita
 
 import (
+	stdContext "context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -28,3 +33,77 @@ func TestAkitaClose(t *testing.T) {
 	err := <-errCh
 	assert.Equal(t, err.Error(), "http: Server closed")
 }
+
+func TestAkitaShutdownDraining(t *testing.T) {
+	e := New()
+	e.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.NoError(t, e.Shutdown(stdContext.Background()))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "5", rec.Header().Get(HeaderRetryAfter))
+
+	// Custom
+	e.SetDrainHandler(func(c Context) error {
+		return c.String(http.StatusServiceUnavailable, "draining")
+	})
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "draining", rec.Body.String())
+}
+
+func TestAkitaStartMulti(t *testing.T) {
+	e := New()
+	e.HideBanner = true
+	e.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	addrs := make([]string, 2)
+	for i := range addrs {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		addrs[i] = l.Addr().String()
+		l.Close()
+	}
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- e.StartMulti(addrs...)
+	}()
+
+	for _, addr := range addrs {
+		var (
+			res *http.Response
+			err error
+		)
+		for i := 0; i < 20; i++ {
+			if res, err = http.Get("http://" + addr); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if assert.NoError(t, err) {
+			b, _ := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			assert.Equal(t, "OK", string(b))
+		}
+	}
+
+	assert.NoError(t, e.Shutdown(stdContext.Background()))
+	assert.Equal(t, http.ErrServerClosed, <-errCh)
+	for _, addr := range addrs {
+		_, err := net.Dial("tcp", addr)
+		assert.Error(t, err)
+	}
+}
@@ -2,9 +2,14 @@ package akita
 
 import (
 	"bytes"
+	stdContext "context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"text/template"
 
 	"reflect"
 	"strings"
@@ -13,7 +18,10 @@ import (
 
 	"time"
 
+	"github.com/itchenyi/common/log"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
 )
 
 type (
@@ -137,6 +145,39 @@ func TestAkitaMiddleware(t *testing.T) {
 	assert.Equal(t, "OK", b)
 }
 
+func TestAkitaResetMiddleware(t *testing.T) {
+	a := New()
+	buf := new(bytes.Buffer)
+	mw := func(s string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx Context) error {
+				buf.WriteString(s)
+				return next(ctx)
+			}
+		}
+	}
+	a.Pre(mw("-1"))
+	a.Use(mw("1"))
+	a.GET("/", func(ctx Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	})
+
+	request(GET, "/", a)
+	assert.Equal(t, "-11", buf.String())
+
+	buf.Reset()
+	a.ResetMiddleware()
+	request(GET, "/", a)
+	assert.Equal(t, "-1", buf.String())
+
+	buf.Reset()
+	a.ResetPreMiddleware()
+	c, b := request(GET, "/", a)
+	assert.Empty(t, buf.String())
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "OK", b)
+}
+
 func TestAkitaMiddlewareError(t *testing.T) {
 	a := New()
 	a.Use(func(next HandlerFunc) HandlerFunc {
@@ -177,6 +218,34 @@ func TestAkitaWrapHandler(t *testing.T) {
 	}
 }
 
+func TestAkitaJSONHandler(t *testing.T) {
+	a := New()
+	a.GET("/users/:id", JSONHandler(func(ctx Context) (interface{}, error) {
+		id, err := ctx.ParamInt("id")
+		if err != nil {
+			return nil, err
+		}
+		if id != 1 {
+			return nil, ErrNotFound
+		}
+		return user{1, "Jon Snow"}, nil
+	}))
+	a.DELETE("/users/:id", JSONHandler(func(ctx Context) (interface{}, error) {
+		return nil, nil
+	}))
+
+	c, b := request(GET, "/users/1", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, userJSON, b)
+	c, _ = request(GET, "/users/2", a)
+	assert.Equal(t, http.StatusNotFound, c)
+	c, _ = request(GET, "/users/jon", a)
+	assert.Equal(t, http.StatusBadRequest, c)
+	c, b = request(DELETE, "/users/1", a)
+	assert.Equal(t, http.StatusNoContent, c)
+	assert.Empty(t, b)
+}
+
 func TestAkitaWrapMiddleware(t *testing.T) {
 	a := New()
 	req := httptest.NewRequest(GET, "/", nil)
@@ -258,6 +327,57 @@ func TestAkitaMatch(t *testing.T) { // JFC
 	})
 }
 
+func TestAkitaExcept(t *testing.T) {
+	a := New()
+	routes := a.Except([]string{TRACE, CONNECT}, "/", func(ctx Context) error {
+		return ctx.String(http.StatusOK, "Except")
+	})
+	assert.Len(t, routes, len(methods)-2)
+
+	c, b := request(GET, "/", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "Except", b)
+	c, _ = request(DELETE, "/", a)
+	assert.Equal(t, http.StatusOK, c)
+	c, _ = request(TRACE, "/", a)
+	assert.Equal(t, http.StatusMethodNotAllowed, c)
+}
+
+func TestAkitaAddPaths(t *testing.T) {
+	a := New()
+	home := func(ctx Context) error {
+		return ctx.String(http.StatusOK, "home")
+	}
+	routes := a.AddPaths(GET, []string{"/home", "/index", "/"}, home)
+	assert.Len(t, routes, 3)
+	g := a.Group("/v1")
+	g.AddPaths(GET, []string{"/home", "/index"}, home)
+
+	for _, p := range []string{"/home", "/index", "/", "/v1/home", "/v1/index"} {
+		c, b := request(GET, p, a)
+		assert.Equal(t, http.StatusOK, c, p)
+		assert.Equal(t, "home", b, p)
+	}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "/home", a.URI(home))
+	}
+}
+
+func TestAkitaRouteData(t *testing.T) {
+	a := New()
+	a.GET("/report", func(ctx Context) error {
+		return ctx.String(http.StatusOK, ctx.Route().Data["timeout"].(time.Duration).String())
+	}).SetData("timeout", 30*time.Second)
+	a.GET("/users/:id", func(ctx Context) error {
+		return ctx.String(http.StatusOK, ctx.Route().Path)
+	})
+
+	_, b := request(GET, "/report", a)
+	assert.Equal(t, "30s", b)
+	_, b = request(GET, "/users/1", a)
+	assert.Equal(t, "/users/:id", b)
+}
+
 func TestAkitaURL(t *testing.T) {
 	a := New()
 	static := func(Context) error { return nil }
@@ -276,13 +396,40 @@ func TestAkitaURL(t *testing.T) {
 	assert.Equal(t, "/group/users/1/files/1", a.URL(getFile, "1", "1"))
 }
 
+func TestAkitaReverse(t *testing.T) {
+	a := New()
+	h := func(Context) error { return nil }
+	a.GET("/users/:id", h).Name = "user"
+	a.GET("/static/*", h).Name = "static"
+	a.GET("/docs/:version/*", h).Name = "docs"
+
+	assert.Equal(t, "/users/1", a.Reverse("user", 1))
+	assert.Equal(t, "/users/jon%20snow%2Fstark", a.Reverse("user", "jon snow/stark"))
+	assert.Equal(t, "/static/*", a.Reverse("static"))
+	assert.Equal(t, "/static/css/app%20v2.css", a.Reverse("static", "css/app v2.css"))
+	assert.Equal(t, "/static/css/app.css", a.Reverse("static", "css", "app.css"))
+	assert.Equal(t, "/docs/v1/guide/intro", a.Reverse("docs", "v1", "guide/intro"))
+}
+
+func TestAkitaReverseEscapedColon(t *testing.T) {
+	a := New()
+	a.GET("/v1/things\\:batchGet", func(ctx Context) error {
+		return ctx.String(http.StatusOK, "batch")
+	}).Name = "batch"
+
+	assert.Equal(t, "/v1/things:batchGet", a.Reverse("batch"))
+	code, body := request(GET, a.Reverse("batch"), a)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "batch", body)
+}
+
 func TestAkitaRoutes(t *testing.T) {
 	a := New()
 	routes := []*Route{
-		{GET, "/users/:user/events", ""},
-		{GET, "/users/:user/events/public", ""},
-		{POST, "/repos/:owner/:repo/git/refs", ""},
-		{POST, "/repos/:owner/:repo/git/tags", ""},
+		{Method: GET, Path: "/users/:user/events"},
+		{Method: GET, Path: "/users/:user/events/public"},
+		{Method: POST, Path: "/repos/:owner/:repo/git/refs"},
+		{Method: POST, Path: "/repos/:owner/:repo/git/tags"},
 	}
 	for _, r := range routes {
 		a.Add(r.Method, r.Path, func(c Context) error {
@@ -375,6 +522,60 @@ func TestAkitaGroup(t *testing.T) {
 	assert.Equal(t, "023", buf.String())
 }
 
+func TestAkitaHost(t *testing.T) {
+	a := New()
+	h := func(body string) HandlerFunc {
+		return func(c Context) error {
+			return c.String(http.StatusOK, body)
+		}
+	}
+	a.GET("/", h("default"))
+	api := a.Host("api.example.com")
+	api.GET("/", h("api"))
+	api.Group("/v1").GET("/users", h("api users"))
+	www := a.Host("www.example.com", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Host", "www")
+			return next(c)
+		}
+	})
+	www.GET("/", h("www"))
+
+	tests := []struct {
+		host, path string
+		code       int
+		body       string
+	}{
+		{"example.com", "/", http.StatusOK, "default"},
+		{"api.example.com", "/", http.StatusOK, "api"},
+		{"api.example.com:8080", "/", http.StatusOK, "api"},
+		{"api.example.com", "/v1/users", http.StatusOK, "api users"},
+		{"www.example.com", "/", http.StatusOK, "www"},
+		{"www.example.com", "/v1/users", http.StatusNotFound, ""},
+		{"example.com", "/v1/users", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(GET, tt.path, nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		assert.Equal(t, tt.code, rec.Code, tt.host+tt.path)
+		if tt.body != "" {
+			assert.Equal(t, tt.body, rec.Body.String())
+		}
+	}
+
+	// Host-level middleware
+	req := httptest.NewRequest(GET, "/missing", nil)
+	req.Host = "www.example.com"
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "www", rec.Header().Get("X-Host"))
+
+	assert.Len(t, a.Routers(), 2)
+}
+
 func TestAkitaNotFound(t *testing.T) {
 	a := New()
 	req := httptest.NewRequest(GET, "/files", nil)
@@ -394,6 +595,66 @@ func TestAkitaMethodNotAllowed(t *testing.T) {
 	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
 }
 
+func TestAkitaSetNotFoundHandler(t *testing.T) {
+	a := New()
+	a.SetNotFoundHandler(func(c Context) error {
+		return c.String(http.StatusNotFound, "nothing here")
+	})
+	a.SetMethodNotAllowedHandler(func(c Context) error {
+		return c.String(http.StatusMethodNotAllowed, "try GET")
+	})
+	a.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "Akita!")
+	})
+
+	c, b := request(GET, "/files", a)
+	assert.Equal(t, http.StatusNotFound, c)
+	assert.Equal(t, "nothing here", b)
+
+	c, b = request(POST, "/", a)
+	assert.Equal(t, http.StatusMethodNotAllowed, c)
+	assert.Equal(t, "try GET", b)
+
+	// Other instances are not affected
+	c, b = request(GET, "/files", New())
+	assert.Equal(t, http.StatusNotFound, c)
+	assert.NotEqual(t, "nothing here", b)
+}
+
+func TestAkitaNotFoundPerInstance(t *testing.T) {
+	a1 := New()
+	a1.SetNotFoundHandler(func(c Context) error {
+		return c.String(http.StatusNotFound, "a1")
+	})
+	a2 := New()
+	a2.SetNotFoundHandler(func(c Context) error {
+		return c.String(http.StatusNotFound, "a2")
+	})
+	g := a2.Group("/g")
+	g.Use(func(next HandlerFunc) HandlerFunc {
+		return next
+	})
+
+	_, b := request(GET, "/files", a1)
+	assert.Equal(t, "a1", b)
+	_, b = request(GET, "/files", a2)
+	assert.Equal(t, "a2", b)
+	_, b = request(GET, "/g/files", a2)
+	assert.Equal(t, "a2", b)
+
+	// Instances keep the handler they were created with
+	defer func(h HandlerFunc) {
+		NotFoundHandler = h
+	}(NotFoundHandler)
+	a3 := New()
+	NotFoundHandler = func(c Context) error {
+		return c.String(http.StatusNotFound, "global")
+	}
+	c, b := request(GET, "/files", a3)
+	assert.Equal(t, http.StatusNotFound, c)
+	assert.NotEqual(t, "global", b)
+}
+
 func TestAkitaContext(t *testing.T) {
 	a := New()
 	c := a.AcquireContext()
@@ -409,6 +670,82 @@ func TestAkitaStart(t *testing.T) {
 	time.Sleep(200 * time.Millisecond)
 }
 
+func TestAkitaStartupOutput(t *testing.T) {
+	a := New()
+	buf := new(bytes.Buffer)
+	a.Logger.SetOutput(buf)
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1323}
+
+	// No colors when the output isn't a terminal
+	a.setupColorer()
+	a.printBanner()
+	a.printStarted("http", addr)
+	assert.NotContains(t, buf.String(), "\x1b[")
+	assert.Contains(t, buf.String(), "http server started on 127.0.0.1:1323")
+
+	// Port hidden
+	buf.Reset()
+	a.HidePort = true
+	a.printStarted("http", addr)
+	assert.Empty(t, buf.String())
+
+	// Through the logger
+	buf.Reset()
+	a.HidePort = false
+	a.LogStartup = true
+	a.Logger.SetLevel(log.INFO)
+	a.printBanner()
+	a.printStarted("https", addr)
+	assert.NotContains(t, buf.String(), "\x1b[")
+	assert.NotContains(t, buf.String(), "____")
+	assert.Contains(t, buf.String(), "https server started on 127.0.0.1:1323")
+
+	// Custom banner
+	buf.Reset()
+	a.LogStartup = false
+	a.Banner = "Acme Gateway 2.3"
+	a.printBanner()
+	assert.Equal(t, "Acme Gateway 2.3\n", buf.String())
+
+	buf.Reset()
+	a.HideBanner = true
+	a.printBanner()
+	assert.Empty(t, buf.String())
+}
+
+func TestAkitaServerHeader(t *testing.T) {
+	a := New()
+	a.GET("/", func(ctx Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get(HeaderServer))
+
+	a.ServerHeader = "Acme"
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, "Acme", rec.Header().Get(HeaderServer))
+}
+
+func TestAkitaMaxURILength(t *testing.T) {
+	a := New()
+	a.MaxURILength = 16
+	a.GET("/*", func(ctx Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	})
+
+	code, body := request(GET, "/users/1", a)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "OK", body)
+
+	code, _ = request(GET, "/users/1?fields=name,email", a)
+	assert.Equal(t, http.StatusRequestURITooLong, code)
+	code, _ = request(GET, "/"+strings.Repeat("a", 16), a)
+	assert.Equal(t, http.StatusRequestURITooLong, code)
+}
+
 func TestAkitaStartTLS(t *testing.T) {
 	a := New()
 	go func() {
@@ -417,6 +754,45 @@ func TestAkitaStartTLS(t *testing.T) {
 	time.Sleep(200 * time.Millisecond)
 }
 
+func TestAkitaAutoTLSHostWhitelist(t *testing.T) {
+	a := New()
+	a.AutoTLSHostWhitelist("example.com", "www.example.com")
+	a.AutoTLSCacheDir("/var/cache/akita")
+	assert.NoError(t, a.AutoTLSManager.HostPolicy(stdContext.Background(), "example.com"))
+	assert.Error(t, a.AutoTLSManager.HostPolicy(stdContext.Background(), "attacker.example.org"))
+	assert.Equal(t, autocert.DirCache("/var/cache/akita"), a.AutoTLSManager.Cache)
+}
+
+func TestAkitaStartH2C(t *testing.T) {
+	a := New()
+	a.HideBanner = true
+	a.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, c.Request().Proto)
+	})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	a.Listener = l
+	go a.StartH2C("", &http2.Server{})
+
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+	res, err := client.Get("http://" + l.Addr().String())
+	if assert.NoError(t, err) {
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "HTTP/2.0", string(body))
+	}
+}
+
 func testMethod(t *testing.T, method, path string, a *Akita) {
 	p := reflect.ValueOf(path)
 	h := reflect.ValueOf(func(c Context) error {
@@ -441,3 +817,82 @@ func TestHTTPError(t *testing.T) {
 	})
 	assert.Equal(t, "code=400, message=map[code:12]", err.Error())
 }
+
+func TestHTMLErrorHandler(t *testing.T) {
+	a := New()
+	a.HTTPErrorHandler = HTMLErrorHandler(map[int]string{
+		http.StatusNotFound: "<h1>Page {{.message}}</h1>",
+	})
+
+	// Browser
+	req := httptest.NewRequest(GET, "/missing", nil)
+	req.Header.Set(HeaderAccept, "text/html,application/xhtml+xml,*/*;q=0.8")
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, MIMETextHTMLCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "<h1>Page Not Found</h1>", rec.Body.String())
+
+	// API
+	req = httptest.NewRequest(GET, "/missing", nil)
+	req.Header.Set(HeaderAccept, MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, `{"message":"Not Found"}`, rec.Body.String())
+
+	// Unmapped status
+	a.GET("/teapot", func(c Context) error {
+		return NewHTTPError(http.StatusTeapot)
+	})
+	req = httptest.NewRequest(GET, "/teapot", nil)
+	req.Header.Set(HeaderAccept, MIMETextHTML)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<h1>418</h1>")
+}
+
+func TestRenderErrorHandler(t *testing.T) {
+	a := New()
+	a.HTTPErrorHandler = RenderErrorHandler("")
+
+	// No renderer
+	req := httptest.NewRequest(GET, "/missing", nil)
+	req.Header.Set(HeaderAccept, MIMETextHTML)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, `{"message":"Not Found"}`, rec.Body.String())
+
+	// Browser
+	a.Renderer = &Template{
+		templates: template.Must(template.New("error.html").Parse("<h1>{{.code}} {{.message}}</h1>")),
+	}
+	req = httptest.NewRequest(GET, "/missing", nil)
+	req.Header.Set(HeaderAccept, "text/html,application/xhtml+xml,*/*;q=0.8")
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, MIMETextHTMLCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "<h1>404 Not Found</h1>", rec.Body.String())
+
+	// API
+	req = httptest.NewRequest(GET, "/missing", nil)
+	req.Header.Set(HeaderAccept, MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, `{"message":"Not Found"}`, rec.Body.String())
+
+	// Missing template
+	a.HTTPErrorHandler = RenderErrorHandler("oops.html")
+	req = httptest.NewRequest(GET, "/missing", nil)
+	req.Header.Set(HeaderAccept, MIMETextHTML)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, `{"message":"Not Found"}`, rec.Body.String())
+}
@@ -0,0 +1,70 @@
+// Package akitatest provides utilities for testing akita handlers.
+//
+// Example:
+//
+//	ctx, rec := akitatest.NewContext(akita.GET, "/users/1", nil)
+//	akitatest.SetParams(ctx, "id", "1")
+//	if err := getUser(ctx); err == nil {
+//	  // Inspect rec.Code, rec.Body
+//	}
+package akitatest
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/itchenyi/akita"
+)
+
+// NewContext returns a Context for a request with method, target and body
+// along with the recorder the response is written to. The Context belongs to
+// a new `akita.Akita` instance.
+func NewContext(method, target string, body io.Reader) (akita.Context, *httptest.ResponseRecorder) {
+	return NewContextWithAkita(akita.New(), method, target, body)
+}
+
+// NewContextWithAkita is like `NewContext()` but uses the provided instance, so
+// its binder, validator and renderer are used by the Context.
+func NewContextWithAkita(a *akita.Akita, method, target string, body io.Reader) (akita.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, body)
+	rec := httptest.NewRecorder()
+	return a.NewContext(req, rec), rec
+}
+
+// SetParams sets the path parameters from name/value pairs, e.g.
+// `SetParams(ctx, "id", "1", "name", "joe")`.
+func SetParams(ctx akita.Context, pairs ...string) {
+	if len(pairs)%2 != 0 {
+		panic("akitatest: params must be name/value pairs")
+	}
+	names := make([]string, 0, len(pairs)/2)
+	values := make([]string, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		names = append(names, pairs[i])
+		values = append(values, pairs[i+1])
+	}
+	ctx.SetParamNames(names...)
+	ctx.SetParamValues(values...)
+}
+
+// SetHeader sets the request header.
+func SetHeader(ctx akita.Context, key, value string) {
+	ctx.Request().Header.Set(key, value)
+}
+
+// SetForm replaces the request body with the URL encoded form values and sets
+// the `Content-Type` header accordingly.
+func SetForm(ctx akita.Context, values url.Values) {
+	SetBody(ctx, akita.MIMEApplicationForm, values.Encode())
+}
+
+// SetBody replaces the request body and sets the `Content-Type` header.
+func SetBody(ctx akita.Context, contentType, body string) {
+	req := ctx.Request()
+	req.Header.Set(akita.HeaderContentType, contentType)
+	req.Body = ioutil.NopCloser(strings.NewReader(body))
+	req.ContentLength = int64(len(body))
+}
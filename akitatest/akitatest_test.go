@@ -0,0 +1,60 @@
+package akitatest
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   int    `json:"id" form:"id"`
+	Name string `json:"name" form:"name"`
+}
+
+func TestNewContextParams(t *testing.T) {
+	ctx, rec := NewContext(akita.GET, "/users/1/posts/2", nil)
+	SetParams(ctx, "id", "1", "post", "2")
+	h := func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, ctx.Param("id")+":"+ctx.Param("post"))
+	}
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1:2", rec.Body.String())
+	}
+
+	assert.Panics(t, func() {
+		SetParams(ctx, "id")
+	})
+}
+
+func TestNewContextJSON(t *testing.T) {
+	ctx, rec := NewContext(akita.POST, "/users", strings.NewReader(`{"id":1,"name":"Jon Snow"}`))
+	SetHeader(ctx, akita.HeaderContentType, akita.MIMEApplicationJSON)
+	h := func(ctx akita.Context) error {
+		u := new(user)
+		if err := ctx.Bind(u); err != nil {
+			return err
+		}
+		return ctx.JSON(http.StatusCreated, u)
+	}
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, `{"id":1,"name":"Jon Snow"}`, rec.Body.String())
+	}
+}
+
+func TestSetForm(t *testing.T) {
+	a := akita.New()
+	ctx, _ := NewContextWithAkita(a, akita.POST, "/users", nil)
+	SetForm(ctx, url.Values{"id": {"1"}, "name": {"Jon Snow"}})
+	u := new(user)
+	if assert.NoError(t, ctx.Bind(u)) {
+		assert.Equal(t, 1, u.ID)
+		assert.Equal(t, "Jon Snow", u.Name)
+	}
+	assert.Equal(t, a, ctx.Akita())
+}
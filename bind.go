@@ -1,14 +1,20 @@
 package akita
 
 import (
+	"encoding"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type (
@@ -20,6 +26,18 @@ type (
 	// DefaultBinder is the default implementation of the Binder interface.
 	DefaultBinder struct{}
 
+	// BindError describes a form or query value which couldn't be bound to the
+	// field of a struct.
+	BindError struct {
+		Field   string   `json:"field"`
+		Message string   `json:"message"`
+		Values  []string `json:"values,omitempty"`
+	}
+
+	// BindErrors holds the errors of all the fields which failed to bind. It is
+	// the message of the `HTTPError` returned by `DefaultBinder#Bind()`.
+	BindErrors []*BindError
+
 	// BindUnmarshaler is the interface used to wrap the UnmarshalParam method.
 	BindUnmarshaler interface {
 		// UnmarshalParam decodes and assigns a value from an form or query param.
@@ -27,13 +45,25 @@ type (
 	}
 )
 
+func (be *BindError) Error() string {
+	return fmt.Sprintf("field '%s' %s", be.Field, be.Message)
+}
+
+func (errs BindErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, be := range errs {
+		msgs[i] = be.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
 // Bind implements the `Binder#Bind` function.
 func (b *DefaultBinder) Bind(i interface{}, ctx Context) (err error) {
 	req := ctx.Request()
 	if req.ContentLength == 0 {
 		if req.Method == GET || req.Method == DELETE {
 			if err = b.bindData(i, ctx.QueryParams(), "query"); err != nil {
-				return NewHTTPError(http.StatusBadRequest, err.Error())
+				return bindDataError(err)
 			}
 			return
 		}
@@ -44,7 +74,7 @@ func (b *DefaultBinder) Bind(i interface{}, ctx Context) (err error) {
 	case strings.HasPrefix(ctype, MIMEApplicationJSON):
 		if err = json.NewDecoder(req.Body).Decode(i); err != nil {
 			if ute, ok := err.(*json.UnmarshalTypeError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, offset=%v", ute.Type, ute.Value, ute.Offset))
+				return NewHTTPError(http.StatusBadRequest, unmarshalTypeErrorMessage(ute))
 			} else if se, ok := err.(*json.SyntaxError); ok {
 				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: offset=%v, error=%v", se.Offset, se.Error()))
 			} else {
@@ -57,17 +87,29 @@ func (b *DefaultBinder) Bind(i interface{}, ctx Context) (err error) {
 				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v, error=%v", ute.Type, ute.Error()))
 			} else if se, ok := err.(*xml.SyntaxError); ok {
 				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v, error=%v", se.Line, se.Error()))
+			} else if ne, ok := err.(*strconv.NumError); ok {
+				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("value '%s' expected number", ne.Num))
 			} else {
 				return NewHTTPError(http.StatusBadRequest, err.Error())
 			}
 		}
 	case strings.HasPrefix(ctype, MIMEApplicationForm), strings.HasPrefix(ctype, MIMEMultipartForm):
+		if err = parseFormBody(req); err != nil {
+			if he, ok := err.(*HTTPError); ok {
+				return he
+			}
+			return NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		params, err := ctx.FormParams()
 		if err != nil {
 			return NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 		if err = b.bindData(i, params, "form"); err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error())
+			return bindDataError(err)
+		}
+		// The multipart form has already been parsed by `FormParams()`
+		if req.MultipartForm != nil {
+			b.bindFiles(i, req.MultipartForm.File, "form")
 		}
 	default:
 		return ErrUnsupportedMediaType
@@ -75,14 +117,133 @@ func (b *DefaultBinder) Bind(i interface{}, ctx Context) (err error) {
 	return
 }
 
-func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag string) error {
-	typ := reflect.TypeOf(ptr).Elem()
+// maxFormBodySize is the size limit of the bodies parsed by `parseFormBody()`,
+// the one of `http.Request#ParseForm()`.
+const maxFormBodySize = 10 << 20
+
+// parseFormBody parses the url-encoded body of the requests whose body
+// `http.Request#ParseForm()` ignores, e.g. DELETE, so that it is part of the
+// form as for POST. It returns `ErrStatusRequestEntityTooLarge` for the bodies
+// over 10MB.
+func parseFormBody(req *http.Request) error {
+	if req.PostForm != nil || !strings.HasPrefix(req.Header.Get(HeaderContentType), MIMEApplicationForm) {
+		return nil
+	}
+	switch req.Method {
+	case POST, PUT, PATCH:
+		return nil
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(req.Body, maxFormBodySize+1))
+	if err != nil {
+		return err
+	}
+	if len(b) > maxFormBodySize {
+		return ErrStatusRequestEntityTooLarge
+	}
+	req.PostForm, err = url.ParseQuery(string(b))
+	return err
+}
+
+// bindDataError maps an error of `bindData()` to a 400 `HTTPError`, keeping
+// the field-level details of `BindErrors`.
+func bindDataError(err error) *HTTPError {
+	if errs, ok := err.(BindErrors); ok {
+		return NewHTTPError(http.StatusBadRequest, errs)
+	}
+	return NewHTTPError(http.StatusBadRequest, err.Error())
+}
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// bindFiles assigns the uploaded files to `*multipart.FileHeader` and
+// `[]*multipart.FileHeader` fields. Embedded structs are flattened.
+func (b *DefaultBinder) bindFiles(ptr interface{}, files map[string][]*multipart.FileHeader, tag string) {
 	val := reflect.ValueOf(ptr).Elem()
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		typeField := typ.Field(i)
+		structField := val.Field(i)
+		if !structField.CanSet() {
+			continue
+		}
+		if typeField.Anonymous && structField.Kind() == reflect.Struct {
+			b.bindFiles(structField.Addr().Interface(), files, tag)
+			continue
+		}
+		name := typeField.Tag.Get(tag)
+		if name == "" {
+			name = typeField.Name
+		}
+		fhs := files[name]
+		if len(fhs) == 0 {
+			continue
+		}
+		switch typeField.Type {
+		case fileHeaderType:
+			structField.Set(reflect.ValueOf(fhs[0]))
+		case fileHeadersType:
+			structField.Set(reflect.ValueOf(fhs))
+		}
+	}
+}
+
+// unmarshalTypeErrorMessage describes a JSON type mismatch in terms of the
+// offending field, e.g. "field 'id' expected number, got string", the field
+// being known from Go 1.8.
+func unmarshalTypeErrorMessage(ute *json.UnmarshalTypeError) string {
+	field := unmarshalTypeErrorField(ute)
+	if field == "" {
+		return fmt.Sprintf("expected %s, got %s", jsonTypeName(ute.Type), ute.Value)
+	}
+	return fmt.Sprintf("field '%s' expected %s, got %s", field, jsonTypeName(ute.Type), ute.Value)
+}
 
+// jsonTypeName returns the JSON name of the Go type.
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "value"
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonTypeName(t.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return t.String()
+}
+
+func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag string) error {
+	typ := reflect.TypeOf(ptr).Elem()
 	if typ.Kind() != reflect.Struct {
 		return errors.New("Binding element must be a struct")
 	}
+	return b.bindStruct(reflect.ValueOf(ptr).Elem(), data, tag, "", map[reflect.Type]bool{})
+}
 
+// bindStruct binds data into the struct value. Nested structs are bound from
+// dotted keys like `address.city`, embedded structs are flattened and share
+// the prefix of their parent. Struct types already being bound higher up in
+// the tree are skipped to guard against cycles. Values which can't be bound
+// don't stop the binding, they are reported together as `BindErrors`.
+func (b *DefaultBinder) bindStruct(val reflect.Value, data map[string][]string, tag, prefix string, seen map[reflect.Type]bool) error {
+	typ := val.Type()
+	seen[typ] = true
+	defer delete(seen, typ)
+
+	errs := BindErrors{}
 	for i := 0; i < typ.NumField(); i++ {
 		typeField := typ.Field(i)
 		structField := val.Field(i)
@@ -91,27 +252,72 @@ func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag
 		}
 		structFieldKind := structField.Kind()
 		inputFieldName := typeField.Tag.Get(tag)
-
-		if inputFieldName == "" {
+		tagged := inputFieldName != ""
+		if !tagged {
 			inputFieldName = typeField.Name
-			// If tag is nil, we inspect if the field is a struct.
-			if _, ok := bindUnmarshaler(structField); !ok && structFieldKind == reflect.Struct {
-				err := b.bindData(structField.Addr().Interface(), data, tag)
-				if err != nil {
-					return err
+		}
+
+		// Nested struct, bound from dotted keys
+		if nested, ok := nestedStruct(structField); ok && !(typeField.Anonymous && !tagged) {
+			if nestedPrefix := prefix + inputFieldName + "."; hasKeyPrefix(data, nestedPrefix) {
+				if seen[nested] {
+					continue
+				}
+				if structFieldKind == reflect.Ptr && structField.IsNil() {
+					structField.Set(reflect.New(nested))
+				}
+				if err := b.bindStruct(reflect.Indirect(structField), data, tag, nestedPrefix, seen); err != nil {
+					nestedErrs, ok := err.(BindErrors)
+					if !ok {
+						return err
+					}
+					errs = append(errs, nestedErrs...)
 				}
 				continue
 			}
 		}
+
+		// If tag is nil, we inspect if the field is a struct.
+		if !tagged && !isUnmarshaler(structField) && structFieldKind == reflect.Struct {
+			if err := b.bindStruct(structField, data, tag, prefix, seen); err != nil {
+				nestedErrs, ok := err.(BindErrors)
+				if !ok {
+					return err
+				}
+				errs = append(errs, nestedErrs...)
+			}
+			continue
+		}
+		inputFieldName = prefix + inputFieldName
 		inputValue, exists := data[inputFieldName]
 		if !exists {
-			continue
+			// Fall back to the `default` tag value for zero valued fields. Fields
+			// present in the request, even with a zero value, are left untouched.
+			def, ok := typeField.Tag.Lookup("default")
+			if !ok || !isZeroValue(structField) {
+				continue
+			}
+			inputValue = []string{def}
+		}
+
+		// Time with a custom layout
+		if layout, ok := typeField.Tag.Lookup("time_format"); ok {
+			if ok, err := setTimeField(inputValue[0], layout, structField); ok {
+				if err != nil {
+					errs = append(errs, &BindError{
+						Field:   inputFieldName,
+						Message: fmt.Sprintf("expected time formatted as '%s'", layout),
+						Values:  inputValue,
+					})
+				}
+				continue
+			}
 		}
 
 		// Call this first, in case we're dealing with an alias to an array type
 		if ok, err := unmarshalField(typeField.Type.Kind(), inputValue[0], structField); ok {
 			if err != nil {
-				return err
+				errs = append(errs, newBindError(inputFieldName, inputValue, typeField.Type, err))
 			}
 			continue
 		}
@@ -120,21 +326,41 @@ func (b *DefaultBinder) bindData(ptr interface{}, data map[string][]string, tag
 		if structFieldKind == reflect.Slice && numElems > 0 {
 			sliceOf := structField.Type().Elem().Kind()
 			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
-			for j := 0; j < numElems; j++ {
-				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
-					return err
-				}
+			var err error
+			for j := 0; j < numElems && err == nil; j++ {
+				err = setWithProperType(sliceOf, inputValue[j], slice.Index(j))
+			}
+			if err != nil {
+				errs = append(errs, newBindError(inputFieldName, inputValue, structField.Type().Elem(), err))
+				continue
 			}
 			val.Field(i).Set(slice)
 		} else {
 			if err := setWithProperType(typeField.Type.Kind(), inputValue[0], structField); err != nil {
-				return err
+				errs = append(errs, newBindError(inputFieldName, inputValue, typeField.Type, err))
 			}
 		}
 	}
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
+// newBindError describes the error of binding values to a field of type t.
+// Parse errors are reported as the expected type, e.g. "expected number".
+func newBindError(field string, values []string, t reflect.Type, err error) *BindError {
+	msg := err.Error()
+	if _, ok := err.(*strconv.NumError); ok {
+		msg = "expected " + jsonTypeName(t)
+	}
+	return &BindError{Field: field, Message: msg, Values: values}
+}
+
+func isZeroValue(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
 func setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
 	// But also call it here, in case we're dealing with an array of BindUnmarshalers
 	if ok, err := unmarshalField(valueKind, val, structField); ok {
@@ -199,12 +425,59 @@ func bindUnmarshaler(field reflect.Value) (BindUnmarshaler, bool) {
 	return nil, false
 }
 
+// nestedStruct returns the struct type of a struct or pointer to struct field
+// that should be traversed rather than unmarshaled.
+func nestedStruct(field reflect.Value) (reflect.Type, bool) {
+	typ := field.Type()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || isUnmarshaler(reflect.New(typ).Elem()) {
+		return nil, false
+	}
+	return typ, true
+}
+
+func hasKeyPrefix(data map[string][]string, prefix string) bool {
+	for k := range data {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// textUnmarshaler attempts to unmarshal a reflect.Value into an encoding.TextUnmarshaler
+func textUnmarshaler(field reflect.Value) (encoding.TextUnmarshaler, bool) {
+	ptr := reflect.New(field.Type())
+	if ptr.CanInterface() {
+		iface := ptr.Interface()
+		if unmarshaler, ok := iface.(encoding.TextUnmarshaler); ok {
+			return unmarshaler, ok
+		}
+	}
+	return nil, false
+}
+
+func isUnmarshaler(field reflect.Value) bool {
+	if _, ok := bindUnmarshaler(field); ok {
+		return true
+	}
+	_, ok := textUnmarshaler(field)
+	return ok
+}
+
 func unmarshalFieldNonPtr(value string, field reflect.Value) (bool, error) {
 	if unmarshaler, ok := bindUnmarshaler(field); ok {
 		err := unmarshaler.UnmarshalParam(value)
 		field.Set(reflect.ValueOf(unmarshaler).Elem())
 		return true, err
 	}
+	if unmarshaler, ok := textUnmarshaler(field); ok {
+		err := unmarshaler.UnmarshalText([]byte(value))
+		field.Set(reflect.ValueOf(unmarshaler).Elem())
+		return true, err
+	}
 	return false, nil
 }
 
@@ -216,6 +489,33 @@ func unmarshalFieldPtr(value string, field reflect.Value) (bool, error) {
 	return unmarshalFieldNonPtr(value, field.Elem())
 }
 
+// setTimeField parses the value using the layout if the field is a `time.Time`
+// or a `*time.Time`. It returns false for any other type.
+func setTimeField(value, layout string, field reflect.Value) (bool, error) {
+	timeType := reflect.TypeOf(time.Time{})
+	if field.Kind() == reflect.Ptr {
+		if field.Type().Elem() != timeType {
+			return false, nil
+		}
+		if field.IsNil() {
+			field.Set(reflect.New(timeType))
+		}
+		field = field.Elem()
+	}
+	if field.Type() != timeType {
+		return false, nil
+	}
+	if value == "" {
+		field.Set(reflect.Zero(timeType))
+		return true, nil
+	}
+	t, err := time.Parse(layout, value)
+	if err == nil {
+		field.Set(reflect.ValueOf(t))
+	}
+	return true, err
+}
+
 func setIntField(value string, bitSize int, field reflect.Value) error {
 	if value == "" {
 		value = "0"
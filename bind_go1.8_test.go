@@ -0,0 +1,22 @@
+// +build go1.8
+
+package akita
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindJSONTypeErrorField(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(POST, "/", strings.NewReader(`{"id":"1","name":"Jon Snow"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+	err := c.Bind(new(user))
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, "field 'id' expected number, got string", err.(*HTTPError).Message)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
 	"testing"
@@ -120,6 +121,57 @@ func TestBindJSON(t *testing.T) {
 	testBindError(t, strings.NewReader(invalidContent), MIMEApplicationJSON)
 }
 
+func TestBindJSONTypeError(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(POST, "/", strings.NewReader(`{"id":"1","name":"Jon Snow"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+	err := c.Bind(new(user))
+	if assert.IsType(t, new(HTTPError), err) {
+		he := err.(*HTTPError)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		// The field is known from Go 1.8, see `TestBindJSONTypeErrorField`
+		assert.Contains(t, he.Message, "expected number, got string")
+	}
+
+	req = httptest.NewRequest(POST, "/", strings.NewReader(`<user><id>one</id></user>`))
+	req.Header.Set(HeaderContentType, MIMEApplicationXML)
+	c = e.NewContext(req, httptest.NewRecorder())
+	err = c.Bind(new(user))
+	if assert.IsType(t, new(HTTPError), err) {
+		he := err.(*HTTPError)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		assert.Equal(t, "value 'one' expected number", he.Message)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	type filter struct {
+		Limit  int    `query:"limit"`
+		Active bool   `query:"active"`
+		Name   string `query:"name"`
+	}
+	a := New()
+	req := httptest.NewRequest(GET, "/?limit=ten&active=maybe&name=Jon", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	err := ctx.Bind(new(filter))
+	if assert.IsType(t, new(HTTPError), err) {
+		he := err.(*HTTPError)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		assert.Equal(t, BindErrors{
+			{Field: "limit", Message: "expected number", Values: []string{"ten"}},
+			{Field: "active", Message: "expected bool", Values: []string{"maybe"}},
+		}, he.Message)
+	}
+
+	a.HTTPErrorHandler(err, ctx)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"message":"Invalid request data","errors":[
+		{"field":"limit","message":"expected number","values":["ten"]},
+		{"field":"active","message":"expected bool","values":["maybe"]}]}`, rec.Body.String())
+}
+
 func TestBindXML(t *testing.T) {
 	testBindOkay(t, strings.NewReader(userXML), MIMEApplicationXML)
 	testBindError(t, strings.NewReader(invalidContent), MIMEApplicationXML)
@@ -140,6 +192,38 @@ func TestBindForm(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestBindBodyAnyMethod(t *testing.T) {
+	e := New()
+	for _, c := range []struct {
+		method, body, ctype string
+	}{
+		{DELETE, userJSON, MIMEApplicationJSON},
+		{GET, userJSON, MIMEApplicationJSON},
+		{DELETE, userXML, MIMEApplicationXML},
+		{DELETE, userForm, MIMEApplicationForm},
+		{GET, userForm, MIMEApplicationForm},
+	} {
+		req := httptest.NewRequest(c.method, "/", strings.NewReader(c.body))
+		req.Header.Set(HeaderContentType, c.ctype)
+		ctx := e.NewContext(req, httptest.NewRecorder())
+		u := new(user)
+		if assert.NoError(t, ctx.Bind(u), c.method+" "+c.ctype) {
+			assert.Equal(t, 1, u.ID)
+			assert.Equal(t, "Jon Snow", u.Name)
+		}
+	}
+
+	// Form body over the limit
+	body := "name=" + strings.Repeat("a", maxFormBodySize)
+	req := httptest.NewRequest(DELETE, "/", strings.NewReader(body))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	ctx := e.NewContext(req, httptest.NewRecorder())
+	err := ctx.Bind(new(user))
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusRequestEntityTooLarge, err.(*HTTPError).Code)
+	}
+}
+
 func TestBindQueryParams(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(GET, "/?id=1&name=Jon+Snow", nil)
@@ -198,6 +282,41 @@ func TestBindMultipartForm(t *testing.T) {
 	testBindOkay(t, body, mw.FormDataContentType())
 }
 
+func TestBindMultipartFormFiles(t *testing.T) {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	mw.WriteField("name", "Jon Snow")
+	fw, _ := mw.CreateFormFile("avatar", "avatar.png")
+	fw.Write([]byte("avatar"))
+	for _, name := range []string{"a.txt", "b.txt"} {
+		fw, _ = mw.CreateFormFile("docs", name)
+		fw.Write([]byte(name))
+	}
+	mw.Close()
+
+	e := New()
+	req := httptest.NewRequest(POST, "/", body)
+	req.Header.Set(HeaderContentType, mw.FormDataContentType())
+	c := e.NewContext(req, httptest.NewRecorder())
+	form := new(struct {
+		Name   string                  `form:"name"`
+		Avatar *multipart.FileHeader   `form:"avatar"`
+		Docs   []*multipart.FileHeader `form:"docs"`
+		Resume *multipart.FileHeader   `form:"resume"`
+	})
+	if assert.NoError(t, c.Bind(form)) {
+		assert.Equal(t, "Jon Snow", form.Name)
+		if assert.NotNil(t, form.Avatar) {
+			assert.Equal(t, "avatar.png", form.Avatar.Filename)
+		}
+		if assert.Len(t, form.Docs, 2) {
+			assert.Equal(t, "a.txt", form.Docs[0].Filename)
+			assert.Equal(t, "b.txt", form.Docs[1].Filename)
+		}
+		assert.Nil(t, form.Resume)
+	}
+}
+
 func TestBindUnsupportedMediaType(t *testing.T) {
 	testBindError(t, strings.NewReader(invalidContent), MIMEApplicationJSON)
 }
@@ -332,3 +451,122 @@ func testBindError(t *testing.T, r io.Reader, ctype string) {
 		}
 	}
 }
+
+func TestBindDefault(t *testing.T) {
+	type query struct {
+		Page   int    `query:"page" default:"1"`
+		Sort   string `query:"sort" default:"name"`
+		Desc   bool   `query:"desc" default:"true"`
+		Filter string `query:"filter"`
+	}
+	e := New()
+
+	// Present
+	req := httptest.NewRequest(GET, "/?page=5&sort=id&desc=false", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	q := new(query)
+	if assert.NoError(t, c.Bind(q)) {
+		assert.Equal(t, query{Page: 5, Sort: "id", Desc: false}, *q)
+	}
+
+	// Absent
+	req = httptest.NewRequest(GET, "/", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	q = new(query)
+	if assert.NoError(t, c.Bind(q)) {
+		assert.Equal(t, query{Page: 1, Sort: "name", Desc: true}, *q)
+	}
+
+	// Explicit zero
+	req = httptest.NewRequest(GET, "/?page=0&sort=", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	q = new(query)
+	if assert.NoError(t, c.Bind(q)) {
+		assert.Equal(t, query{Page: 0, Sort: "", Desc: true}, *q)
+	}
+
+	// Invalid default
+	type invalid struct {
+		Page int `query:"page" default:"one"`
+	}
+	req = httptest.NewRequest(GET, "/", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.Error(t, c.Bind(new(invalid)))
+}
+
+func TestBindTime(t *testing.T) {
+	type query struct {
+		From  time.Time   `query:"from" time_format:"2006-01-02"`
+		To    *time.Time  `query:"to" time_format:"2006-01-02"`
+		At    time.Time   `query:"at"`
+		Dates []time.Time `query:"dates"`
+	}
+	e := New()
+	req := httptest.NewRequest(GET, "/?from=2023-01-02&to=2023-02-03&at=2016-12-06T19:09:05Z&dates=2016-12-06T19:09:05Z", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	q := new(query)
+	if assert.NoError(t, c.Bind(q)) {
+		assert.Equal(t, time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), q.From)
+		assert.Equal(t, time.Date(2023, 2, 3, 0, 0, 0, 0, time.UTC), *q.To)
+		assert.Equal(t, time.Date(2016, 12, 6, 19, 9, 5, 0, time.UTC), q.At)
+		assert.Equal(t, []time.Time{time.Date(2016, 12, 6, 19, 9, 5, 0, time.UTC)}, q.Dates)
+	}
+
+	// Parse failure
+	req = httptest.NewRequest(GET, "/?from=01/02/2023", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+	err := c.Bind(new(query))
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+}
+
+func TestBindNestedStruct(t *testing.T) {
+	type (
+		address struct {
+			City    string `form:"city"`
+			Country struct {
+				Code string `form:"code"`
+			} `form:"country"`
+		}
+		Meta struct {
+			Source string `form:"source"`
+		}
+		node struct {
+			Name string `form:"name"`
+			Next *node  `form:"next"`
+		}
+		user struct {
+			Meta
+			Name    string   `form:"name"`
+			Address address  `form:"address"`
+			Billing *address `form:"billing"`
+			Node    node     `form:"node"`
+		}
+	)
+	e := New()
+	f := make(url.Values)
+	f.Set("name", "Jon Snow")
+	f.Set("source", "web")
+	f.Set("address.city", "NYC")
+	f.Set("address.country.code", "US")
+	f.Set("billing.city", "LA")
+	f.Set("node.name", "a")
+	f.Set("node.next.name", "b")
+	req := httptest.NewRequest(POST, "/", strings.NewReader(f.Encode()))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	c := e.NewContext(req, httptest.NewRecorder())
+	u := new(user)
+	if assert.NoError(t, c.Bind(u)) {
+		assert.Equal(t, "Jon Snow", u.Name)
+		assert.Equal(t, "web", u.Source)
+		assert.Equal(t, "NYC", u.Address.City)
+		assert.Equal(t, "US", u.Address.Country.Code)
+		if assert.NotNil(t, u.Billing) {
+			assert.Equal(t, "LA", u.Billing.City)
+		}
+		assert.Equal(t, "a", u.Node.Name)
+		// Cyclic types are not traversed
+		assert.Nil(t, u.Node.Next)
+	}
+}
@@ -2,43 +2,95 @@ package akita
 
 import (
 	"bytes"
+	stdContext "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"mime"
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/http/httputil"
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type (
 	// Context represents the context of the current HTTP request. It holds request and
 	// response objects, path, path parameters, data and registered handler.
 	Context interface {
+		// fsContext adds the `io/fs` based methods on Go 1.16+, e.g.
+		// `FileFS(file string, fsys fs.FS) error`.
+		fsContext
+
 		// Request returns `*http.Request`.
 		Request() *http.Request
 
 		// SetRequest sets `*http.Request`.
 		SetRequest(r *http.Request)
 
+		// Context returns the `context.Context` of the request. It is canceled
+		// when the client's connection closes.
+		Context() stdContext.Context
+
+		// WithContext replaces the request with a shallow copy carrying the
+		// provided `context.Context`.
+		WithContext(c stdContext.Context)
+
 		// Response returns `*Response`.
 		Response() *Response
 
 		// IsTLS returns true if HTTP connection is TLS otherwise false.
 		IsTLS() bool
 
+		// TLSConnectionState returns the state of the TLS connection, nil if the
+		// HTTP connection isn't TLS.
+		TLSConnectionState() *tls.ConnectionState
+
+		// ClientCertificate returns the leaf certificate presented by the client
+		// on a mutual TLS connection. `ErrClientCertificateRequired` is returned
+		// if there is none.
+		ClientCertificate() (*x509.Certificate, error)
+
 		// IsWebSocket returns true if HTTP connection is WebSocket otherwise false.
 		IsWebSocket() bool
 
+		// ContentType returns the lowercase media type of the request body without
+		// parameters, e.g. "application/json" for "application/json; charset=UTF-8".
+		ContentType() string
+
+		// IsJSON returns true if the request body is JSON, including media types
+		// with a `+json` suffix.
+		IsJSON() bool
+
+		// IsXML returns true if the request body is XML, including media types
+		// with a `+xml` suffix.
+		IsXML() bool
+
+		// IsForm returns true if the request body is a URL-encoded form.
+		IsForm() bool
+
+		// IsMultipart returns true if the request body is a multipart form.
+		IsMultipart() bool
+
 		// Scheme returns the HTTP protocol scheme, `http` or `https`.
 		Scheme() string
 
-		// RealIP returns the client's network address based on `X-Forwarded-For`
-		// or `X-Real-IP` request header.
+		// RealIP returns the client's network address using `Akita#IPExtractor`.
+		// If it is not set, the address is based on `X-Forwarded-For` or
+		// `X-Real-IP` request header.
 		RealIP() string
 
 		// Path returns the registered path for the handler.
@@ -47,9 +99,21 @@ type (
 		// SetPath sets the registered path for the handler.
 		SetPath(p string)
 
+		// Route returns the route matched for the request or nil if there is
+		// none, e.g. before routing in `Pre()` middleware.
+		Route() *Route
+
 		// Param returns path parameter by name.
 		Param(name string) string
 
+		// ParamInt returns path parameter by name converted to an int. A
+		// missing or unparseable parameter results in an `*HTTPError` with
+		// status 400, which handlers can return as is.
+		ParamInt(name string) (int, error)
+
+		// ParamDefault returns path parameter by name or def if it is empty.
+		ParamDefault(name, def string) string
+
 		// ParamNames returns path parameter names.
 		ParamNames() []string
 
@@ -74,6 +138,11 @@ type (
 		// FormValue returns the form field value for the provided name.
 		FormValue(name string) string
 
+		// FormValues returns all the form field values for the provided name,
+		// e.g. for a group of checkboxes. It returns an empty slice if there
+		// are none or the form can't be parsed.
+		FormValues(name string) []string
+
 		// FormParams returns the form parameters as `url.Values`.
 		FormParams() (url.Values, error)
 
@@ -83,6 +152,11 @@ type (
 		// MultipartForm returns the multipart form.
 		MultipartForm() (*multipart.Form, error)
 
+		// Body returns the raw request body. The body is read once and cached, and
+		// the request body is replaced so that it can still be read by handlers
+		// and the binder.
+		Body() ([]byte, error)
+
 		// Cookie returns the named cookie provided in the request.
 		Cookie(name string) (*http.Cookie, error)
 
@@ -92,12 +166,49 @@ type (
 		// Cookies returns the HTTP cookies sent with the request.
 		Cookies() []*http.Cookie
 
+		// SignedCookie returns the named cookie provided in the request after
+		// verifying its HMAC-SHA256 signature with key. The returned cookie has
+		// the signature stripped from its value. `ErrInvalidCookieSignature` is
+		// returned if the signature is missing or doesn't match.
+		SignedCookie(name string, key []byte) (*http.Cookie, error)
+
+		// SetSignedCookie adds a `Set-Cookie` header in HTTP response with the
+		// value of the cookie signed using HMAC-SHA256 with key.
+		SetSignedCookie(cookie *http.Cookie, key []byte)
+
+		// ClearCookie adds a `Set-Cookie` header in HTTP response expiring the
+		// named cookie. The path and domain, set with `CookiePath()` and
+		// `CookieDomain()`, must match the ones the cookie was set with for the
+		// browser to drop it.
+		ClearCookie(name string, opts ...CookieOption)
+
+		// CacheControl sets the `Cache-Control` header in HTTP response.
+		CacheControl(value string)
+
+		// SetLastModified sets the `Last-Modified` header in HTTP response.
+		SetLastModified(t time.Time)
+
+		// NotModified reports whether the content last modified at t is unchanged
+		// since the `If-Modified-Since` time of a GET or HEAD request, in which
+		// case the handler can respond with `NoContent(http.StatusNotModified)`.
+		// It reports false if the request has an `If-None-Match` header, which
+		// takes precedence.
+		NotModified(t time.Time) bool
+
 		// Get retrieves data from the context.
 		Get(key string) interface{}
 
 		// Set saves data in the context.
 		Set(key string, val interface{})
 
+		// SetValue saves data in the request's `context.Context`, for the
+		// libraries reading it through `context.Value`. As with
+		// `context.WithValue`, key must be comparable and should be of a
+		// package's own type rather than a built-in one such as string, to
+		// avoid collisions. Only a string key is also saved in the context, for
+		// `Get`, the typed keys staying out of its string keyed store.
+		SetValue(key, val interface{})
+
 		// Bind binds the request body into provided type `i`. The default binder
 		// does it based on Content-Type header.
 		Bind(i interface{}) error
@@ -110,6 +221,11 @@ type (
 		// code. Renderer must be registered using `Akita.Renderer`.
 		Render(code int, name string, data interface{}) error
 
+		// RenderToString renders a template with data and returns the result
+		// without writing the response, e.g. to compose an email body.
+		// Renderer must be registered using `Akita.Renderer`.
+		RenderToString(name string, data interface{}) (string, error)
+
 		// HTML sends an HTTP response with status code.
 		HTML(code int, html string) error
 
@@ -119,12 +235,19 @@ type (
 		// String sends a string response with status code.
 		String(code int, s string) error
 
-		// JSON sends a JSON response with status code.
+		// JSON sends a JSON response with status code. It is pretty-printed in
+		// debug mode, or if `Akita#JSONPrettyQuery` is set and the request has
+		// the `pretty` query param.
 		JSON(code int, i interface{}) error
 
 		// JSONPretty sends a pretty-print JSON with status code.
 		JSONPretty(code int, i interface{}, indent string) error
 
+		// Problem sends problem details, see RFC 7807, with status code and
+		// `application/problem+json` content type. The status of the problem
+		// defaults to code.
+		Problem(code int, problem Problem) error
+
 		// JSONBlob sends a JSON blob response with status code.
 		JSONBlob(code int, b []byte) error
 
@@ -145,19 +268,60 @@ type (
 		// XMLBlob sends an XML blob response with status code.
 		XMLBlob(code int, b []byte) error
 
-		// Blob sends a blob response with status code and content type.
+		// Blob sends a blob response with status code and content type. The
+		// `Content-Length` header is set unless already present.
 		Blob(code int, contentType string, b []byte) error
 
 		// Stream sends a streaming response with status code and content type.
 		Stream(code int, contentType string, r io.Reader) error
 
+		// StreamFlush is like `Stream()` but flushes the response after every
+		// chunk read from r. It stops and returns `ErrStreamCanceled` once the
+		// request's context is done, e.g. when the client disconnects.
+		StreamFlush(code int, contentType string, r io.Reader) error
+
+		// JSONLines sends a newline delimited JSON response with status code,
+		// one line per item received from items until it is closed. The
+		// response is flushed whenever no item is ready. It stops and returns
+		// `ErrStreamCanceled` once the request's context is done.
+		JSONLines(code int, items <-chan interface{}) error
+
+		// Proxy forwards the request to the upstream target, e.g.
+		// `http://10.0.0.2:8080`, and streams its response back, for one-off
+		// gateway routes. The `X-Forwarded-For`, `X-Forwarded-Host`,
+		// `X-Forwarded-Proto` and `X-Real-IP` headers are set. Upstream errors
+		// are returned as an `*HTTPError` with status 502, the proxy itself
+		// sending the 502 response before Go 1.11.
+		Proxy(target *url.URL) error
+
+		// Multipart sends the headers of a multipart/mixed response with status
+		// code and returns the writer of its parts, e.g. for batch APIs, along
+		// with the function to call once they are written, which closes the
+		// response with the final boundary.
+		Multipart(code int) (*multipart.Writer, func() error)
+
 		// File sends a response with the content of the file.
 		File(file string) error
 
+		// FileWithStatus sends a response with status code and the content of the
+		// file, e.g. a custom error page. Unlike `File()` conditional and range
+		// requests aren't handled.
+		FileWithStatus(file string, code int) error
+
+		// ServeReader sends a response with the content of the reader. It handles
+		// `Range`, `If-Range` and `If-Modified-Since` requests, and the content
+		// type is taken from the extension of `name`, looked up in
+		// `Akita#MIMETypes` first, or sniffed from the content.
+		ServeReader(name string, modtime time.Time, content io.ReadSeeker) error
+
 		// Attachment sends a response as attachment, prompting client to save the
 		// file.
 		Attachment(file string, name string) error
 
+		// AttachmentReader sends the content of the reader as attachment named
+		// `name`, prompting client to save it. Range requests are supported.
+		AttachmentReader(name string, modtime time.Time, content io.ReadSeeker) error
+
 		// Inline sends a response as inline, opening the file in the browser.
 		Inline(file string, name string) error
 
@@ -167,7 +331,26 @@ type (
 		// Redirect redirects the request to a provided URL with status code.
 		Redirect(code int, url string) error
 
+		// SeeOther redirects the request to a provided URL with status code 303,
+		// e.g. after a POST.
+		SeeOther(url string) error
+
+		// TemporaryRedirect redirects the request to a provided URL with status
+		// code 307, preserving the request method and body.
+		TemporaryRedirect(url string) error
+
+		// PermanentRedirect redirects the request to a provided URL with status
+		// code 308, preserving the request method and body.
+		PermanentRedirect(url string) error
+
+		// RedirectToRoute redirects the request with status code to the URL of
+		// the named route, built with `Akita#Reverse()` from params.
+		// `ErrRouteNotFound` is returned if there is no route with this name.
+		RedirectToRoute(code int, name string, params ...interface{}) error
+
 		// Error invokes the registered HTTP error handler. Generally used by middleware.
+		// The handler of the group the matched route belongs to takes precedence
+		// over `Akita#HTTPErrorHandler`, see `Group#SetHTTPErrorHandler()`.
 		Error(err error)
 
 		// Handler returns the matched handler by router.
@@ -179,6 +362,11 @@ type (
 		// Logger returns the `Logger` instance.
 		Logger() Logger
 
+		// Go runs fn in a new goroutine, recovering and logging a panic instead
+		// of crashing the server. fn must not use the Context as it is reused
+		// once the request completes.
+		Go(fn func())
+
 		// Akita returns the `Akita` instance.
 		Akita() *Akita
 
@@ -195,10 +383,17 @@ type (
 		pnames   []string
 		pvalues  []string
 		query    url.Values
+		body     []byte
 		handler  HandlerFunc
 		store    Map
 		akita    *Akita
+		// errorHandler is the HTTP error handler of the group the matched
+		// route belongs to, if any.
+		errorHandler HTTPErrorHandler
 	}
+
+	// CookieOption configures the cookie written by `Context#ClearCookie()`.
+	CookieOption func(*http.Cookie)
 )
 
 const (
@@ -214,6 +409,14 @@ func (ctx *context) SetRequest(r *http.Request) {
 	ctx.request = r
 }
 
+func (ctx *context) Context() stdContext.Context {
+	return ctx.request.Context()
+}
+
+func (ctx *context) WithContext(c stdContext.Context) {
+	ctx.request = ctx.request.WithContext(c)
+}
+
 func (ctx *context) Response() *Response {
 	return ctx.response
 }
@@ -222,11 +425,53 @@ func (ctx *context) IsTLS() bool {
 	return ctx.request.TLS != nil
 }
 
+func (ctx *context) TLSConnectionState() *tls.ConnectionState {
+	return ctx.request.TLS
+}
+
+func (ctx *context) ClientCertificate() (*x509.Certificate, error) {
+	if ctx.request.TLS == nil || len(ctx.request.TLS.PeerCertificates) == 0 {
+		return nil, ErrClientCertificateRequired
+	}
+	return ctx.request.TLS.PeerCertificates[0], nil
+}
+
 func (ctx *context) IsWebSocket() bool {
 	upgrade := ctx.request.Header.Get(HeaderUpgrade)
 	return upgrade == "websocket" || upgrade == "Websocket"
 }
 
+func (ctx *context) ContentType() string {
+	ctype := ctx.request.Header.Get(HeaderContentType)
+	if ctype == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(ctype)
+	if err != nil {
+		// Malformed parameters, use the media type as is
+		mt = strings.ToLower(strings.TrimSpace(strings.Split(ctype, ";")[0]))
+	}
+	return mt
+}
+
+func (ctx *context) IsJSON() bool {
+	mt := ctx.ContentType()
+	return mt == MIMEApplicationJSON || strings.HasSuffix(mt, "+json")
+}
+
+func (ctx *context) IsXML() bool {
+	mt := ctx.ContentType()
+	return mt == MIMEApplicationXML || mt == MIMETextXML || strings.HasSuffix(mt, "+xml")
+}
+
+func (ctx *context) IsForm() bool {
+	return ctx.ContentType() == MIMEApplicationForm
+}
+
+func (ctx *context) IsMultipart() bool {
+	return ctx.ContentType() == MIMEMultipartForm
+}
+
 func (ctx *context) Scheme() string {
 	// Can't use `r.Request.URL.Scheme`
 	// See: https://groups.google.com/forum/#!topic/golang-nuts/pMUkBlQBDF0
@@ -249,6 +494,9 @@ func (ctx *context) Scheme() string {
 }
 
 func (ctx *context) RealIP() string {
+	if ctx.akita != nil && ctx.akita.IPExtractor != nil {
+		return ctx.akita.IPExtractor(ctx.request)
+	}
 	ra := ctx.request.RemoteAddr
 	if ip := ctx.request.Header.Get(HeaderXForwardedFor); ip != "" {
 		ra = strings.Split(ip, ", ")[0]
@@ -264,6 +512,10 @@ func (ctx *context) Path() string {
 	return ctx.path
 }
 
+func (ctx *context) Route() *Route {
+	return ctx.akita.FindRoute(ctx.request.Host, ctx.request.Method, ctx.path)
+}
+
 func (ctx *context) SetPath(p string) {
 	ctx.path = p
 }
@@ -286,6 +538,27 @@ func (ctx *context) Param(name string) string {
 	return ""
 }
 
+func (ctx *context) ParamInt(name string) (int, error) {
+	v := ctx.Param(name)
+	if v == "" {
+		return 0, NewHTTPError(http.StatusBadRequest, "Missing path parameter "+name)
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		he := NewHTTPError(http.StatusBadRequest, "Invalid path parameter "+name)
+		he.Inner = err
+		return 0, he
+	}
+	return i, nil
+}
+
+func (ctx *context) ParamDefault(name, def string) string {
+	if v := ctx.Param(name); v != "" {
+		return v
+	}
+	return def
+}
+
 func (ctx *context) ParamNames() []string {
 	return ctx.pnames
 }
@@ -324,9 +597,17 @@ func (ctx *context) FormValue(name string) string {
 	return ctx.request.FormValue(name)
 }
 
+func (ctx *context) FormValues(name string) []string {
+	params, err := ctx.FormParams()
+	if err != nil || len(params[name]) == 0 {
+		return []string{}
+	}
+	return params[name]
+}
+
 func (ctx *context) FormParams() (url.Values, error) {
-	if strings.HasPrefix(ctx.request.Header.Get(HeaderContentType), MIMEMultipartForm) {
-		if err := ctx.request.ParseMultipartForm(defaultMemory); err != nil {
+	if ctx.IsMultipart() {
+		if err := ctx.request.ParseMultipartForm(ctx.akita.MaxMultipartMemory); err != nil {
 			return nil, err
 		}
 	} else {
@@ -338,15 +619,36 @@ func (ctx *context) FormParams() (url.Values, error) {
 }
 
 func (ctx *context) FormFile(name string) (*multipart.FileHeader, error) {
-	_, fh, err := ctx.request.FormFile(name)
-	return fh, err
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if fhs := form.File[name]; len(fhs) > 0 {
+		return fhs[0], nil
+	}
+	return nil, http.ErrMissingFile
 }
 
 func (ctx *context) MultipartForm() (*multipart.Form, error) {
-	err := ctx.request.ParseMultipartForm(defaultMemory)
+	err := ctx.request.ParseMultipartForm(ctx.akita.MaxMultipartMemory)
 	return ctx.request.MultipartForm, err
 }
 
+func (ctx *context) Body() ([]byte, error) {
+	if ctx.body == nil {
+		b := []byte{}
+		if ctx.request.Body != nil {
+			var err error
+			if b, err = ioutil.ReadAll(ctx.request.Body); err != nil {
+				return nil, err
+			}
+		}
+		ctx.body = b
+	}
+	ctx.request.Body = ioutil.NopCloser(bytes.NewReader(ctx.body)) // Reset
+	return ctx.body, nil
+}
+
 func (ctx *context) Cookie(name string) (*http.Cookie, error) {
 	return ctx.request.Cookie(name)
 }
@@ -359,6 +661,90 @@ func (ctx *context) Cookies() []*http.Cookie {
 	return ctx.request.Cookies()
 }
 
+func (ctx *context) SignedCookie(name string, key []byte) (*http.Cookie, error) {
+	cookie, err := ctx.request.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+	i := strings.LastIndexByte(cookie.Value, '.')
+	if i == -1 {
+		return nil, ErrInvalidCookieSignature
+	}
+	value, sig := cookie.Value[:i], cookie.Value[i+1:]
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, cookieSignature(name, value, key)) {
+		return nil, ErrInvalidCookieSignature
+	}
+	c := *cookie
+	c.Value = value
+	return &c, nil
+}
+
+func (ctx *context) SetSignedCookie(cookie *http.Cookie, key []byte) {
+	c := *cookie
+	c.Value += "." + base64.RawURLEncoding.EncodeToString(cookieSignature(c.Name, c.Value, key))
+	ctx.SetCookie(&c)
+}
+
+func (ctx *context) ClearCookie(name string, opts ...CookieOption) {
+	cookie := &http.Cookie{
+		Name:    name,
+		Path:    "/",
+		MaxAge:  -1,
+		Expires: time.Unix(0, 0),
+	}
+	for _, opt := range opts {
+		opt(cookie)
+	}
+	ctx.SetCookie(cookie)
+}
+
+// CookiePath sets the path of the cookie, "/" by default.
+func CookiePath(path string) CookieOption {
+	return func(c *http.Cookie) {
+		c.Path = path
+	}
+}
+
+// CookieDomain sets the domain of the cookie.
+func CookieDomain(domain string) CookieOption {
+	return func(c *http.Cookie) {
+		c.Domain = domain
+	}
+}
+
+// cookieSignature signs the name along with the value so a signed value can't
+// be reused for another cookie.
+func cookieSignature(name, value string, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name + "=" + value))
+	return mac.Sum(nil)
+}
+
+func (ctx *context) CacheControl(value string) {
+	ctx.response.Header().Set(HeaderCacheControl, value)
+}
+
+func (ctx *context) SetLastModified(t time.Time) {
+	ctx.response.Header().Set(HeaderLastModified, t.UTC().Format(http.TimeFormat))
+}
+
+func (ctx *context) NotModified(t time.Time) bool {
+	req := ctx.request
+	if req.Method != GET && req.Method != HEAD {
+		return false
+	}
+	if t.IsZero() || req.Header.Get(HeaderIfNoneMatch) != "" {
+		return false
+	}
+	since, err := http.ParseTime(req.Header.Get(HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	// The header has a one second resolution
+	return !t.Truncate(time.Second).After(since)
+}
+
 func (ctx *context) Get(key string) interface{} {
 	return ctx.store[key]
 }
@@ -370,6 +756,13 @@ func (ctx *context) Set(key string, val interface{}) {
 	ctx.store[key] = val
 }
 
+func (ctx *context) SetValue(key, val interface{}) {
+	ctx.WithContext(stdContext.WithValue(ctx.request.Context(), key, val))
+	if k, ok := key.(string); ok {
+		ctx.Set(k, val)
+	}
+}
+
 func (ctx *context) Bind(i interface{}) error {
 	return ctx.akita.Binder.Bind(i, ctx)
 }
@@ -382,14 +775,30 @@ func (ctx *context) Validate(i interface{}) error {
 }
 
 func (ctx *context) Render(code int, name string, data interface{}) (err error) {
+	buf, err := ctx.render(name, data)
+	if err != nil {
+		return
+	}
+	return ctx.HTMLBlob(code, buf.Bytes())
+}
+
+func (ctx *context) RenderToString(name string, data interface{}) (string, error) {
+	buf, err := ctx.render(name, data)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (ctx *context) render(name string, data interface{}) (*bytes.Buffer, error) {
 	if ctx.akita.Renderer == nil {
-		return ErrRendererNotRegistered
+		return nil, ErrRendererNotRegistered
 	}
 	buf := new(bytes.Buffer)
-	if err = ctx.akita.Renderer.Render(buf, name, data, ctx); err != nil {
-		return
+	if err := ctx.akita.Renderer.Render(buf, name, data, ctx); err != nil {
+		return nil, err
 	}
-	return ctx.HTMLBlob(code, buf.Bytes())
+	return buf, nil
 }
 
 func (ctx *context) HTML(code int, html string) (err error) {
@@ -405,11 +814,14 @@ func (ctx *context) String(code int, s string) (err error) {
 }
 
 func (ctx *context) JSON(code int, i interface{}) (err error) {
-	_, pretty := ctx.QueryParams()["pretty"]
-	if ctx.akita.Debug || pretty {
+	pretty := ctx.akita.Debug
+	if !pretty && ctx.akita.JSONPrettyQuery {
+		_, pretty = ctx.QueryParams()["pretty"]
+	}
+	if pretty {
 		return ctx.JSONPretty(code, i, "  ")
 	}
-	b, err := json.Marshal(i)
+	b, err := ctx.marshalJSON(i, "")
 	if err != nil {
 		return
 	}
@@ -417,13 +829,40 @@ func (ctx *context) JSON(code int, i interface{}) (err error) {
 }
 
 func (ctx *context) JSONPretty(code int, i interface{}, indent string) (err error) {
-	b, err := json.MarshalIndent(i, "", indent)
+	b, err := ctx.marshalJSON(i, indent)
 	if err != nil {
 		return
 	}
 	return ctx.JSONBlob(code, b)
 }
 
+// marshalJSON returns the JSON encoding of i, indented with indent if not
+// empty. HTML characters are escaped unless `Akita#JSONEscapeHTML` is false.
+func (ctx *context) marshalJSON(i interface{}, indent string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(ctx.akita.JSONEscapeHTML)
+	if indent != "" {
+		enc.SetIndent("", indent)
+	}
+	if err := enc.Encode(i); err != nil {
+		return nil, err
+	}
+	// Trim the newline added by the encoder
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+func (ctx *context) Problem(code int, problem Problem) error {
+	if problem.Status == 0 {
+		problem.Status = code
+	}
+	b, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+	return ctx.Blob(code, MIMEApplicationProblemJSON, b)
+}
+
 func (ctx *context) JSONBlob(code int, b []byte) (err error) {
 	return ctx.Blob(code, MIMEApplicationJSONCharsetUTF8, b)
 }
@@ -480,7 +919,12 @@ func (ctx *context) XMLBlob(code int, b []byte) (err error) {
 }
 
 func (ctx *context) Blob(code int, contentType string, b []byte) (err error) {
-	ctx.response.Header().Set(HeaderContentType, contentType)
+	header := ctx.response.Header()
+	header.Set(HeaderContentType, contentType)
+	// Keep the length set by a middleware, e.g. for a transformed body
+	if !ctx.response.Committed && header.Get(HeaderContentLength) == "" {
+		header.Set(HeaderContentLength, strconv.Itoa(len(b)))
+	}
 	ctx.response.WriteHeader(code)
 	_, err = ctx.response.Write(b)
 	return
@@ -493,10 +937,95 @@ func (ctx *context) Stream(code int, contentType string, r io.Reader) (err error
 	return
 }
 
+func (ctx *context) StreamFlush(code int, contentType string, r io.Reader) error {
+	ctx.response.Header().Set(HeaderContentType, contentType)
+	ctx.response.WriteHeader(code)
+	flusher, _ := ctx.response.Writer.(http.Flusher)
+	done := ctx.request.Context().Done()
+	buf := make([]byte, 32*1024)
+	for {
+		select {
+		case <-done:
+			return ErrStreamCanceled
+		default:
+		}
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := ctx.response.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (ctx *context) Proxy(target *url.URL) (err error) {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		if req.Header.Get(HeaderXRealIP) == "" {
+			req.Header.Set(HeaderXRealIP, ctx.RealIP())
+		}
+		if req.Header.Get(HeaderXForwardedHost) == "" {
+			req.Header.Set(HeaderXForwardedHost, ctx.request.Host)
+		}
+		if req.Header.Get(HeaderXForwardedProto) == "" {
+			req.Header.Set(HeaderXForwardedProto, ctx.Scheme())
+		}
+	}
+	handleProxyError(proxy, func(e error) {
+		he := NewHTTPError(http.StatusBadGateway)
+		he.Inner = e
+		err = he
+	})
+	proxy.ServeHTTP(ctx.response, ctx.request)
+	return
+}
+
+func (ctx *context) Multipart(code int) (*multipart.Writer, func() error) {
+	w := multipart.NewWriter(ctx.response)
+	ctx.response.Header().Set(HeaderContentType, mime.FormatMediaType(MIMEMultipartMixed, map[string]string{"boundary": w.Boundary()}))
+	ctx.response.WriteHeader(code)
+	return w, w.Close
+}
+
+func (ctx *context) JSONLines(code int, items <-chan interface{}) error {
+	ctx.response.Header().Set(HeaderContentType, MIMEApplicationNDJSON)
+	ctx.response.WriteHeader(code)
+	flusher, _ := ctx.response.Writer.(http.Flusher)
+	done := ctx.request.Context().Done()
+	enc := json.NewEncoder(ctx.response)
+	for {
+		select {
+		case <-done:
+			return ErrStreamCanceled
+		case item, ok := <-items:
+			if !ok {
+				return nil
+			}
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+			if flusher != nil && len(items) == 0 {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
 func (ctx *context) File(file string) (err error) {
 	f, err := os.Open(file)
 	if err != nil {
-		return NotFoundHandler(ctx)
+		return ctx.akita.notFoundHandler(ctx)
 	}
 	defer f.Close()
 
@@ -505,21 +1034,56 @@ func (ctx *context) File(file string) (err error) {
 		file = filepath.Join(file, indexPage)
 		f, err = os.Open(file)
 		if err != nil {
-			return NotFoundHandler(ctx)
+			return ctx.akita.notFoundHandler(ctx)
 		}
 		defer f.Close()
 		if fi, err = f.Stat(); err != nil {
 			return
 		}
 	}
-	http.ServeContent(ctx.Response(), ctx.Request(), fi.Name(), fi.ModTime(), f)
-	return
+	return ctx.ServeReader(fi.Name(), fi.ModTime(), f)
+}
+
+func (ctx *context) FileWithStatus(file string, code int) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return ctx.akita.notFoundHandler(ctx)
+	}
+	defer f.Close()
+
+	ext := filepath.Ext(file)
+	ct, ok := ctx.akita.MIMETypes[strings.ToLower(ext)]
+	if !ok {
+		ct = mime.TypeByExtension(ext)
+	}
+	if ct == "" {
+		ct = MIMEOctetStream
+	}
+	return ctx.Stream(code, ct, f)
+}
+
+func (ctx *context) ServeReader(name string, modtime time.Time, content io.ReadSeeker) error {
+	// Content type by extension from `Akita#MIMETypes`, otherwise the one
+	// of the `mime` package or sniffed by `http.ServeContent()`
+	header := ctx.response.Header()
+	if header.Get(HeaderContentType) == "" && ctx.akita != nil {
+		if ct, ok := ctx.akita.MIMETypes[strings.ToLower(filepath.Ext(name))]; ok {
+			header.Set(HeaderContentType, ct)
+		}
+	}
+	http.ServeContent(ctx.Response(), ctx.Request(), name, modtime, content)
+	return nil
 }
 
 func (ctx *context) Attachment(file, name string) (err error) {
 	return ctx.contentDisposition(file, name, "attachment")
 }
 
+func (ctx *context) AttachmentReader(name string, modtime time.Time, content io.ReadSeeker) error {
+	ctx.response.Header().Set(HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+	return ctx.ServeReader(name, modtime, content)
+}
+
 func (ctx *context) Inline(file, name string) (err error) {
 	return ctx.contentDisposition(file, name, "inline")
 }
@@ -544,7 +1108,31 @@ func (ctx *context) Redirect(code int, url string) error {
 	return nil
 }
 
+func (ctx *context) SeeOther(url string) error {
+	return ctx.Redirect(http.StatusSeeOther, url)
+}
+
+func (ctx *context) TemporaryRedirect(url string) error {
+	return ctx.Redirect(http.StatusTemporaryRedirect, url)
+}
+
+func (ctx *context) PermanentRedirect(url string) error {
+	return ctx.Redirect(http.StatusPermanentRedirect, url)
+}
+
+func (ctx *context) RedirectToRoute(code int, name string, params ...interface{}) error {
+	url := ctx.akita.Reverse(name, params...)
+	if url == "" {
+		return ErrRouteNotFound
+	}
+	return ctx.Redirect(code, url)
+}
+
 func (ctx *context) Error(err error) {
+	if ctx.errorHandler != nil {
+		ctx.errorHandler(err, ctx)
+		return
+	}
 	ctx.akita.HTTPErrorHandler(err, ctx)
 }
 
@@ -564,11 +1152,27 @@ func (ctx *context) Logger() Logger {
 	return ctx.akita.Logger
 }
 
+func (ctx *context) Go(fn func()) {
+	logger := ctx.Logger()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := make([]byte, 4<<10) // 4 KB
+				length := runtime.Stack(stack, false)
+				logger.Printf("[PANIC RECOVER] %v %s\n", r, stack[:length])
+			}
+		}()
+		fn()
+	}()
+}
+
 func (ctx *context) Reset(r *http.Request, w http.ResponseWriter) {
 	ctx.request = r
 	ctx.response.reset(w)
 	ctx.query = nil
-	ctx.handler = NotFoundHandler
+	ctx.body = nil
+	ctx.handler = ctx.akita.notFoundHandler
+	ctx.errorHandler = nil
 	ctx.store = nil
 	ctx.path = ""
 	ctx.pnames = nil
@@ -0,0 +1,39 @@
+//go:build !go1.11
+// +build !go1.11
+
+package akita
+
+import (
+	"io/ioutil"
+	stdLog "log"
+	"net/http"
+	"net/http/httputil"
+)
+
+type (
+	// proxyTransport reports the errors of the upstream requests, which
+	// `httputil.ReverseProxy` only logs before Go 1.11.
+	proxyTransport struct {
+		http.RoundTripper
+		fn func(error)
+	}
+)
+
+// handleProxyError reports the upstream errors of proxy to fn, the proxy still
+// sending a 502 response.
+func handleProxyError(proxy *httputil.ReverseProxy, fn func(error)) {
+	transport := proxy.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	proxy.Transport = &proxyTransport{RoundTripper: transport, fn: fn}
+	proxy.ErrorLog = stdLog.New(ioutil.Discard, "", 0)
+}
+
+func (t *proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		t.fn(err)
+	}
+	return res, err
+}
@@ -0,0 +1,17 @@
+//go:build go1.11
+// +build go1.11
+
+package akita
+
+import (
+	"net/http"
+	"net/http/httputil"
+)
+
+// handleProxyError reports the upstream errors of proxy to fn instead of
+// sending a 502 response.
+func handleProxyError(proxy *httputil.ReverseProxy, fn func(error)) {
+	proxy.ErrorHandler = func(_ http.ResponseWriter, _ *http.Request, err error) {
+		fn(err)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build go1.18
+// +build go1.18
+
+package akita
+
+import "fmt"
+
+// Get returns the value stored in the context for key as a T. It reports false
+// if there is no value or it isn't a T.
+func Get[T any](ctx Context, key string) (T, bool) {
+	v, ok := ctx.Get(key).(T)
+	return v, ok
+}
+
+// MustGet is like `Get()` but panics if there is no value for key or it isn't
+// a T.
+func MustGet[T any](ctx Context, key string) T {
+	v, ok := Get[T](ctx, key)
+	if !ok {
+		panic(fmt.Sprintf("akita: context value '%s' is %T, not %T", key, ctx.Get(key), v))
+	}
+	return v
+}
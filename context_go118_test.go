@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package akita
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextGetGeneric(t *testing.T) {
+	a := New()
+	ctx := a.NewContext(nil, nil)
+	ctx.Set("user", user{ID: 1, Name: "Jon Snow"})
+	ctx.Set("id", 1)
+
+	u, ok := Get[user](ctx, "user")
+	assert.True(t, ok)
+	assert.Equal(t, "Jon Snow", u.Name)
+	assert.Equal(t, 1, MustGet[int](ctx, "id"))
+
+	// Wrong type
+	_, ok = Get[string](ctx, "id")
+	assert.False(t, ok)
+
+	// Missing key
+	u, ok = Get[user](ctx, "missing")
+	assert.False(t, ok)
+	assert.Equal(t, user{}, u)
+	assert.PanicsWithValue(t, "akita: context value 'missing' is <nil>, not *akita.user", func() {
+		MustGet[*user](ctx, "missing")
+	})
+}
@@ -2,19 +2,31 @@ package akita
 
 import (
 	"bytes"
+	stdContext "context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"text/template"
 	"time"
 
+	"strconv"
 	"strings"
 
 	"net/url"
 
+	"encoding/json"
 	"encoding/xml"
 
 	"github.com/stretchr/testify/assert"
@@ -59,9 +71,21 @@ func TestContext(t *testing.T) {
 		assert.Equal(t, "Hello, Jon Snow!", rec.Body.String())
 	}
 
+	// Render to string
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec).(*context)
+	s, err := ctx.RenderToString("hello", "Arya Stark")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Hello, Arya Stark!", s)
+		assert.False(t, ctx.Response().Committed)
+		assert.Empty(t, rec.Body.String())
+	}
+
 	ctx.akita.Renderer = nil
 	err = ctx.Render(http.StatusOK, "hello", "Jon Snow")
 	assert.Error(t, err)
+	_, err = ctx.RenderToString("hello", "Jon Snow")
+	assert.Equal(t, ErrRendererNotRegistered, err)
 
 	// JSON
 	rec = httptest.NewRecorder()
@@ -78,13 +102,35 @@ func TestContext(t *testing.T) {
 	rec = httptest.NewRecorder()
 	ctx = a.NewContext(req, rec).(*context)
 	err = ctx.JSON(http.StatusOK, user{1, "Jon Snow"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
+		assert.Equal(t, userJSON, rec.Body.String())
+	}
+
+	// JSON with "?pretty" and `JSONPrettyQuery`
+	a.JSONPrettyQuery = true
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec).(*context)
+	err = ctx.JSON(http.StatusOK, user{1, "Jon Snow"})
 	if assert.NoError(t, err) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t, MIMEApplicationJSONCharsetUTF8, rec.Header().Get(HeaderContentType))
 		assert.Equal(t, userJSONPretty, rec.Body.String())
 	}
+	a.JSONPrettyQuery = false
 	req = httptest.NewRequest(GET, "/", nil) // reset
 
+	// JSON in debug mode
+	a.Debug = true
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec).(*context)
+	err = ctx.JSON(http.StatusOK, user{1, "Jon Snow"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, userJSONPretty, rec.Body.String())
+	}
+	a.Debug = false
+
 	// JSONPretty
 	rec = httptest.NewRecorder()
 	ctx = a.NewContext(req, rec).(*context)
@@ -226,6 +272,314 @@ func TestContext(t *testing.T) {
 	assert.Equal(t, 0, len(ctx.QueryParams()))
 }
 
+type chunkReader struct {
+	chunks []string
+	onRead func()
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	if r.onRead != nil {
+		r.onRead()
+	}
+	return n, nil
+}
+
+func TestContextStreamFlush(t *testing.T) {
+	a := New()
+
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	err := ctx.StreamFlush(http.StatusOK, MIMETextPlain, &chunkReader{chunks: []string{"foo", "bar"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MIMETextPlain, rec.Header().Get(HeaderContentType))
+		assert.Equal(t, "foobar", rec.Body.String())
+		assert.True(t, rec.Flushed)
+	}
+
+	// Canceled mid-stream
+	c, cancel := stdContext.WithCancel(stdContext.Background())
+	defer cancel()
+	req = httptest.NewRequest(GET, "/", nil).WithContext(c)
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	err = ctx.StreamFlush(http.StatusOK, MIMETextPlain, &chunkReader{chunks: []string{"foo", "bar"}, onRead: cancel})
+	assert.Equal(t, ErrStreamCanceled, err)
+	assert.Equal(t, "foo", rec.Body.String())
+}
+
+func TestContextJSONLines(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	items := make(chan interface{}, 3)
+	items <- user{1, "Jon Snow"}
+	items <- user{2, "Arya Stark"}
+	items <- map[string]int{"total": 2}
+	close(items)
+	err := ctx.JSONLines(http.StatusOK, items)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MIMEApplicationNDJSON, rec.Header().Get(HeaderContentType))
+		assert.True(t, rec.Flushed)
+		dec := json.NewDecoder(rec.Body)
+		u := user{}
+		if assert.NoError(t, dec.Decode(&u)) {
+			assert.Equal(t, user{1, "Jon Snow"}, u)
+		}
+		if assert.NoError(t, dec.Decode(&u)) {
+			assert.Equal(t, user{2, "Arya Stark"}, u)
+		}
+		total := map[string]int{}
+		if assert.NoError(t, dec.Decode(&total)) {
+			assert.Equal(t, 2, total["total"])
+		}
+		assert.Equal(t, io.EOF, dec.Decode(&total))
+	}
+
+	// Canceled
+	c, cancel := stdContext.WithCancel(stdContext.Background())
+	cancel()
+	req = httptest.NewRequest(GET, "/", nil).WithContext(c)
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	assert.Equal(t, ErrStreamCanceled, ctx.JSONLines(http.StatusOK, make(chan interface{})))
+}
+
+func TestContextJSONEscapeHTML(t *testing.T) {
+	a := New()
+	data := Map{"snippet": "<b>Jon & Arya</b>"}
+
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(httptest.NewRequest(GET, "/", nil), rec)
+	if assert.NoError(t, ctx.JSON(http.StatusOK, data)) {
+		assert.Equal(t, `{"snippet":"\u003cb\u003eJon \u0026 Arya\u003c/b\u003e"}`, rec.Body.String())
+	}
+
+	a.JSONEscapeHTML = false
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(httptest.NewRequest(GET, "/", nil), rec)
+	if assert.NoError(t, ctx.JSON(http.StatusOK, data)) {
+		assert.Equal(t, `{"snippet":"<b>Jon & Arya</b>"}`, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(httptest.NewRequest(GET, "/", nil), rec)
+	if assert.NoError(t, ctx.JSONPretty(http.StatusOK, data, "  ")) {
+		assert.Equal(t, "{\n  \"snippet\": \"<b>Jon & Arya</b>\"\n}", rec.Body.String())
+	}
+}
+
+func TestContextProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("X-Upstream", "yes")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "%s %s %s for=%s host=%s proto=%s", r.Method, r.URL.RequestURI(), body,
+			r.Header.Get(HeaderXForwardedFor), r.Header.Get(HeaderXForwardedHost), r.Header.Get(HeaderXForwardedProto))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL + "/api")
+
+	a := New()
+	a.Any("/*", func(ctx Context) error {
+		return ctx.Proxy(target)
+	})
+	gateway := httptest.NewServer(a)
+	defer gateway.Close()
+
+	req, _ := http.NewRequest(POST, gateway.URL+"/users?active=1", strings.NewReader("Jon"))
+	req.Host = "example.com"
+	res, err := http.DefaultClient.Do(req)
+	if assert.NoError(t, err) {
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, "yes", res.Header.Get("X-Upstream"))
+		assert.Equal(t, "POST /api/users?active=1 Jon for=127.0.0.1 host=example.com proto=http", string(body))
+	}
+
+	// Upstream down
+	upstream.Close()
+	res, err = http.Get(gateway.URL + "/users")
+	if assert.NoError(t, err) {
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadGateway, res.StatusCode)
+	}
+}
+
+func TestContextMultipart(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	w, done := ctx.Multipart(http.StatusOK)
+	for _, body := range []string{`{"id":1}`, `{"id":2}`} {
+		part, err := w.CreatePart(textproto.MIMEHeader{HeaderContentType: {MIMEApplicationJSON}})
+		if assert.NoError(t, err) {
+			part.Write([]byte(body))
+		}
+	}
+	assert.NoError(t, done())
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get(HeaderContentType))
+	if assert.NoError(t, err) {
+		assert.Equal(t, MIMEMultipartMixed, mediaType)
+		r := multipart.NewReader(rec.Body, params["boundary"])
+		for _, body := range []string{`{"id":1}`, `{"id":2}`} {
+			part, err := r.NextPart()
+			if assert.NoError(t, err) {
+				assert.Equal(t, MIMEApplicationJSON, part.Header.Get(HeaderContentType))
+				b, _ := ioutil.ReadAll(part)
+				assert.Equal(t, body, string(b))
+			}
+		}
+		_, err = r.NextPart()
+		assert.Equal(t, io.EOF, err)
+	}
+}
+
+func TestContextClientCertificate(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	ctx := a.NewContext(req, nil)
+	assert.Nil(t, ctx.TLSConnectionState())
+	_, err := ctx.ClientCertificate()
+	assert.Equal(t, ErrClientCertificateRequired, err)
+
+	// TLS without a client certificate
+	req.TLS = &tls.ConnectionState{}
+	_, err = ctx.ClientCertificate()
+	assert.Equal(t, ErrClientCertificateRequired, err)
+
+	pair, err := tls.LoadX509KeyPair("_fixture/certs/cert.pem", "_fixture/certs/key.pem")
+	if !assert.NoError(t, err) {
+		return
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	assert.Equal(t, req.TLS, ctx.TLSConnectionState())
+	c, err := ctx.ClientCertificate()
+	if assert.NoError(t, err) {
+		assert.Equal(t, cert, c)
+	}
+}
+
+func TestContextContentType(t *testing.T) {
+	a := New()
+	tests := []struct {
+		ctype     string
+		mediaType string
+		json      bool
+		xml       bool
+		form      bool
+		multipart bool
+	}{
+		{"", "", false, false, false, false},
+		{MIMEApplicationJSON, MIMEApplicationJSON, true, false, false, false},
+		{MIMEApplicationJSONCharsetUTF8, MIMEApplicationJSON, true, false, false, false},
+		{"Application/JSON;charset=utf-8", MIMEApplicationJSON, true, false, false, false},
+		{"application/problem+json", "application/problem+json", true, false, false, false},
+		{MIMEApplicationXMLCharsetUTF8, MIMEApplicationXML, false, true, false, false},
+		{MIMETextXML, MIMETextXML, false, true, false, false},
+		{MIMEApplicationForm, MIMEApplicationForm, false, false, true, false},
+		{MIMEMultipartForm + "; boundary=xyz", MIMEMultipartForm, false, false, false, true},
+		{MIMETextPlainCharsetUTF8, MIMETextPlain, false, false, false, false},
+		{"application/json; charset", MIMEApplicationJSON, true, false, false, false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(POST, "/", nil)
+		req.Header.Set(HeaderContentType, tt.ctype)
+		ctx := a.NewContext(req, nil)
+		assert.Equal(t, tt.mediaType, ctx.ContentType(), tt.ctype)
+		assert.Equal(t, tt.json, ctx.IsJSON(), tt.ctype)
+		assert.Equal(t, tt.xml, ctx.IsXML(), tt.ctype)
+		assert.Equal(t, tt.form, ctx.IsForm(), tt.ctype)
+		assert.Equal(t, tt.multipart, ctx.IsMultipart(), tt.ctype)
+	}
+}
+
+func TestContextFileMIMETypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "akita-mime")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	wasm := filepath.Join(dir, "app.wasm")
+	ioutil.WriteFile(wasm, []byte("\x00asm\x01\x00\x00\x00"), 0644)
+	custom := filepath.Join(dir, "data.AKITA")
+	ioutil.WriteFile(custom, []byte("akita"), 0644)
+
+	a := New()
+	a.MIMETypes[".akita"] = "application/x-akita"
+	req := httptest.NewRequest(GET, "/", nil)
+
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, ctx.File(wasm)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/wasm", rec.Header().Get(HeaderContentType))
+	}
+
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, ctx.File(custom)) {
+		assert.Equal(t, "application/x-akita", rec.Header().Get(HeaderContentType))
+	}
+
+	// Unmapped
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, ctx.File("_fixture/images/akita.png")) {
+		assert.Equal(t, "image/png", rec.Header().Get(HeaderContentType))
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestContextGo(t *testing.T) {
+	a := New()
+	buf := new(syncBuffer)
+	a.Logger.SetOutput(buf)
+	ctx := a.NewContext(nil, nil)
+
+	done := make(chan struct{})
+	ctx.Go(func() {
+		defer close(done)
+		panic("background failure")
+	})
+	<-done
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "[PANIC RECOVER] background failure")
+	}, time.Second, 10*time.Millisecond)
+}
+
 func TestContextCookie(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(GET, "/", nil)
@@ -269,6 +623,17 @@ func TestContextCookie(t *testing.T) {
 	assert.Contains(t, rec.Header().Get(HeaderSetCookie), "liusha.me")
 	assert.Contains(t, rec.Header().Get(HeaderSetCookie), "Secure")
 	assert.Contains(t, rec.Header().Get(HeaderSetCookie), "HttpOnly")
+
+	// Clear
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec).(*context)
+	c.ClearCookie("SSID", CookiePath("/app"), CookieDomain("liusha.me"))
+	v := rec.Header().Get(HeaderSetCookie)
+	assert.Contains(t, v, "SSID=;")
+	assert.Contains(t, v, "Path=/app")
+	assert.Contains(t, v, "Domain=liusha.me")
+	assert.Contains(t, v, "Max-Age=0")
+	assert.Contains(t, v, "Expires=Thu, 01 Jan 1970 00:00:00 GMT")
 }
 
 func TestContextPath(t *testing.T) {
@@ -303,6 +668,38 @@ func TestContextPathParam(t *testing.T) {
 	assert.Equal(t, "501", c.Param("fid"))
 }
 
+func TestContextParamInt(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	c := e.NewContext(req, nil)
+	c.SetParamNames("id", "name")
+	c.SetParamValues("42", "joe")
+
+	// Numeric
+	i, err := c.ParamInt("id")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 42, i)
+	}
+
+	// Non-numeric
+	_, err = c.ParamInt("name")
+	if assert.IsType(t, new(HTTPError), err) {
+		he := err.(*HTTPError)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		assert.Error(t, he.Inner)
+	}
+
+	// Missing
+	_, err = c.ParamInt("page")
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	}
+
+	// ParamDefault
+	assert.Equal(t, "joe", c.ParamDefault("name", "jon"))
+	assert.Equal(t, "1", c.ParamDefault("page", "1"))
+}
+
 func TestContextPathParamNamesAlais(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(GET, "/", nil)
@@ -339,6 +736,32 @@ func TestContextFormValue(t *testing.T) {
 	}
 }
 
+func TestContextFormValues(t *testing.T) {
+	f := make(url.Values)
+	f.Add("colors", "red")
+	f.Add("colors", "blue")
+	f.Set("name", "Jon Snow")
+
+	e := New()
+	req := httptest.NewRequest(POST, "/", strings.NewReader(f.Encode()))
+	req.Header.Add(HeaderContentType, MIMEApplicationForm)
+	c := e.NewContext(req, nil)
+	assert.Equal(t, []string{"red", "blue"}, c.FormValues("colors"))
+	assert.Equal(t, []string{"Jon Snow"}, c.FormValues("name"))
+	assert.Equal(t, []string{}, c.FormValues("missing"))
+
+	// Multipart
+	buf := new(bytes.Buffer)
+	mw := multipart.NewWriter(buf)
+	mw.WriteField("colors", "green")
+	mw.WriteField("colors", "yellow")
+	mw.Close()
+	req = httptest.NewRequest(POST, "/", buf)
+	req.Header.Set(HeaderContentType, mw.FormDataContentType())
+	c = e.NewContext(req, nil)
+	assert.Equal(t, []string{"green", "yellow"}, c.FormValues("colors"))
+}
+
 func TestContextQueryParam(t *testing.T) {
 	q := make(url.Values)
 	q.Set("name", "Jon Snow")
@@ -375,6 +798,61 @@ func TestContextFormFile(t *testing.T) {
 	if assert.NoError(t, err) {
 		assert.Equal(t, "test", f.Filename)
 	}
+	_, err = c.FormFile("missing")
+	assert.Equal(t, http.ErrMissingFile, err)
+}
+
+func TestContextFileWithStatus(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(GET, "/missing", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(t, c.FileWithStatus("_fixture/index.html", http.StatusNotFound)) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Header().Get(HeaderContentType), MIMETextHTML)
+		assert.Contains(t, rec.Body.String(), "Akita")
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	assert.Equal(t, ErrNotFound, c.FileWithStatus("_fixture/missing.html", http.StatusNotFound))
+}
+
+func TestContextMaxMultipartMemory(t *testing.T) {
+	upload := func(e *Akita) (multipart.File, error) {
+		buf := new(bytes.Buffer)
+		mr := multipart.NewWriter(buf)
+		w, _ := mr.CreateFormFile("file", "test")
+		w.Write([]byte(strings.Repeat("test", 256)))
+		mr.Close()
+		req := httptest.NewRequest(POST, "/", buf)
+		req.Header.Set(HeaderContentType, mr.FormDataContentType())
+		c := e.NewContext(req, httptest.NewRecorder())
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		return fh.Open()
+	}
+
+	// In memory
+	e := New()
+	assert.Equal(t, int64(32<<20), e.MaxMultipartMemory)
+	f, err := upload(e)
+	if assert.NoError(t, err) {
+		_, onDisk := f.(*os.File)
+		assert.False(t, onDisk)
+		f.Close()
+	}
+
+	// Spilled to disk
+	e.MaxMultipartMemory = 16
+	f, err = upload(e)
+	if assert.NoError(t, err) {
+		_, onDisk := f.(*os.File)
+		assert.True(t, onDisk)
+		f.Close()
+	}
 }
 
 func TestContextMultipartForm(t *testing.T) {
@@ -404,6 +882,78 @@ func TestContextRedirect(t *testing.T) {
 	assert.Error(t, c.Redirect(310, "https://liusha.me/tags/akita"))
 }
 
+func TestContextRedirectHelpers(t *testing.T) {
+	e := New()
+	tests := []struct {
+		redirect func(Context, string) error
+		code     int
+	}{
+		{Context.SeeOther, http.StatusSeeOther},
+		{Context.TemporaryRedirect, http.StatusTemporaryRedirect},
+		{Context.PermanentRedirect, http.StatusPermanentRedirect},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(POST, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if assert.NoError(t, tt.redirect(c, "/done?id=1")) {
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, "/done?id=1", rec.Header().Get(HeaderLocation))
+		}
+	}
+}
+
+func TestContextRedirectToRoute(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", func(Context) error { return nil }).Name = "user"
+	req := httptest.NewRequest(POST, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if assert.NoError(t, c.RedirectToRoute(http.StatusSeeOther, "user", 42)) {
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/users/42", rec.Header().Get(HeaderLocation))
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	assert.Equal(t, ErrRouteNotFound, c.RedirectToRoute(http.StatusSeeOther, "unknown"))
+	assert.False(t, c.Response().Committed)
+}
+
+func TestContextNotModified(t *testing.T) {
+	e := New()
+	modified := time.Date(2020, 5, 1, 10, 30, 0, 500, time.UTC)
+	e.Match([]string{GET, HEAD}, "/report", func(c Context) error {
+		c.SetLastModified(modified)
+		if c.NotModified(modified) {
+			return c.NoContent(http.StatusNotModified)
+		}
+		return c.String(http.StatusOK, "report")
+	})
+
+	for _, tt := range []struct {
+		method string
+		header map[string]string
+		code   int
+	}{
+		{GET, nil, http.StatusOK},
+		{GET, map[string]string{HeaderIfModifiedSince: "Fri, 01 May 2020 10:30:00 GMT"}, http.StatusNotModified},
+		{HEAD, map[string]string{HeaderIfModifiedSince: "Sat, 02 May 2020 00:00:00 GMT"}, http.StatusNotModified},
+		{GET, map[string]string{HeaderIfModifiedSince: "Fri, 01 May 2020 10:29:59 GMT"}, http.StatusOK},
+		{GET, map[string]string{HeaderIfModifiedSince: "invalid"}, http.StatusOK},
+		{GET, map[string]string{HeaderIfModifiedSince: "Fri, 01 May 2020 10:30:00 GMT", HeaderIfNoneMatch: `"abc"`}, http.StatusOK},
+	} {
+		req := httptest.NewRequest(tt.method, "/report", nil)
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, tt.code, rec.Code, tt.header)
+		assert.Equal(t, "Fri, 01 May 2020 10:30:00 GMT", rec.Header().Get(HeaderLastModified))
+	}
+}
+
 func TestContextStore(t *testing.T) {
 	var c Context
 	c = new(context)
@@ -411,6 +961,23 @@ func TestContextStore(t *testing.T) {
 	assert.Equal(t, "Jon Snow", c.Get("name"))
 }
 
+func TestContextSetValue(t *testing.T) {
+	type key string
+	e := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	c := e.NewContext(req, nil)
+
+	c.SetValue(key("user"), "Jon Snow")
+	assert.Equal(t, "Jon Snow", c.Request().Context().Value(key("user")))
+	assert.Nil(t, c.Request().Context().Value("user"))
+	assert.Nil(t, c.Get("user"))
+
+	// String keys are also in the store
+	c.SetValue("id", 1)
+	assert.Equal(t, 1, c.Request().Context().Value("id"))
+	assert.Equal(t, 1, c.Get("id"))
+}
+
 func TestContextHandler(t *testing.T) {
 	e := New()
 	r := e.Router()
@@ -425,3 +992,172 @@ func TestContextHandler(t *testing.T) {
 	c.Handler()(c)
 	assert.Equal(t, "handler", b.String())
 }
+
+func TestContextBody(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(POST, "/", strings.NewReader(userJSON))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := a.NewContext(req, rec)
+
+	b1, err := c.Body()
+	if assert.NoError(t, err) {
+		assert.Equal(t, userJSON, string(b1))
+	}
+	b2, err := c.Body()
+	if assert.NoError(t, err) {
+		assert.Equal(t, b1, b2)
+	}
+
+	// Bind after reading the body
+	u := new(user)
+	if assert.NoError(t, c.Bind(u)) {
+		assert.Equal(t, 1, u.ID)
+		assert.Equal(t, "Jon Snow", u.Name)
+	}
+}
+
+func TestContextServeReader(t *testing.T) {
+	a := New()
+	content := strings.NewReader("Hello, World!")
+
+	// Full content
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	c := a.NewContext(req, rec)
+	if assert.NoError(t, c.ServeReader("hello.txt", time.Now(), content)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(HeaderContentType), MIMETextPlain)
+		assert.Equal(t, "Hello, World!", rec.Body.String())
+	}
+
+	// Range
+	req = httptest.NewRequest(GET, "/", nil)
+	req.Header.Set("Range", "bytes=0-3")
+	rec = httptest.NewRecorder()
+	c = a.NewContext(req, rec)
+	if assert.NoError(t, c.ServeReader("hello.txt", time.Now(), content)) {
+		assert.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, "bytes 0-3/13", rec.Header().Get("Content-Range"))
+		assert.Equal(t, "Hell", rec.Body.String())
+	}
+}
+
+func TestContextAttachmentReader(t *testing.T) {
+	a := New()
+	content := bytes.NewReader([]byte("PK generated archive"))
+
+	req := httptest.NewRequest(GET, "/", nil)
+	req.Header.Set("Range", "bytes=3-")
+	rec := httptest.NewRecorder()
+	c := a.NewContext(req, rec)
+	if assert.NoError(t, c.AttachmentReader("archive.zip", time.Now(), content)) {
+		assert.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, `attachment; filename="archive.zip"`, rec.Header().Get(HeaderContentDisposition))
+		assert.Equal(t, "bytes 3-19/20", rec.Header().Get("Content-Range"))
+		assert.Equal(t, "generated archive", rec.Body.String())
+	}
+}
+
+func TestContextCacheControl(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	c := a.NewContext(req, rec)
+	c.CacheControl("no-cache")
+	assert.Equal(t, "no-cache", rec.Header().Get(HeaderCacheControl))
+}
+
+func TestContextContext(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	c := a.NewContext(req, httptest.NewRecorder())
+	assert.Equal(t, req.Context(), c.Context())
+
+	cc, cancel := stdContext.WithCancel(c.Context())
+	c.WithContext(cc)
+	assert.Equal(t, cc, c.Request().Context())
+	select {
+	case <-c.Context().Done():
+		t.Fatal("context canceled early")
+	default:
+	}
+	cancel()
+	select {
+	case <-c.Context().Done():
+		assert.Equal(t, stdContext.Canceled, c.Context().Err())
+	case <-time.After(time.Second):
+		t.Fatal("context not canceled")
+	}
+}
+
+func TestContextBlobContentLength(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(GET, "/", nil)
+
+	rec := httptest.NewRecorder()
+	c := a.NewContext(req, rec)
+	if assert.NoError(t, c.String(http.StatusOK, "Hello, World!")) {
+		assert.Equal(t, "13", rec.Header().Get(HeaderContentLength))
+	}
+
+	rec = httptest.NewRecorder()
+	c = a.NewContext(req, rec)
+	if assert.NoError(t, c.JSON(http.StatusOK, user{1, "Jon Snow"})) {
+		assert.Equal(t, strconv.Itoa(len(userJSON)), rec.Header().Get(HeaderContentLength))
+	}
+
+	// Already set
+	rec = httptest.NewRecorder()
+	c = a.NewContext(req, rec)
+	c.Response().Header().Set(HeaderContentLength, "5")
+	if assert.NoError(t, c.String(http.StatusOK, "Hello")) {
+		assert.Equal(t, []string{"5"}, rec.Header()[HeaderContentLength])
+	}
+}
+
+func TestContextSignedCookie(t *testing.T) {
+	a := New()
+	key := []byte("secret")
+
+	// Round trip
+	rec := httptest.NewRecorder()
+	c := a.NewContext(httptest.NewRequest(GET, "/", nil), rec)
+	cookie := &http.Cookie{Name: "session", Value: "user.1", HttpOnly: true}
+	c.SetSignedCookie(cookie, key)
+	assert.Equal(t, "user.1", cookie.Value)
+	signed := rec.Header().Get(HeaderSetCookie)
+	assert.Contains(t, signed, "session=user.1.")
+	assert.Contains(t, signed, "HttpOnly")
+
+	req := httptest.NewRequest(GET, "/", nil)
+	req.Header.Set(HeaderCookie, strings.SplitN(signed, ";", 2)[0])
+	c = a.NewContext(req, httptest.NewRecorder())
+	if cookie, err := c.SignedCookie("session", key); assert.NoError(t, err) {
+		assert.Equal(t, "user.1", cookie.Value)
+	}
+
+	// Wrong key
+	_, err := c.SignedCookie("session", []byte("other"))
+	assert.Equal(t, ErrInvalidCookieSignature, err)
+
+	// Tampered, missing signature, and signature of another cookie
+	value := strings.TrimPrefix(strings.SplitN(signed, ";", 2)[0], "session=")
+	for _, v := range []string{
+		"session=user.2" + value[len("user.1"):],
+		"session=user",
+		"session=user.1.",
+		"other=" + value,
+	} {
+		req = httptest.NewRequest(GET, "/", nil)
+		req.Header.Set(HeaderCookie, v)
+		c = a.NewContext(req, httptest.NewRecorder())
+		name := strings.SplitN(v, "=", 2)[0]
+		_, err = c.SignedCookie(name, key)
+		assert.Equal(t, ErrInvalidCookieSignature, err, v)
+	}
+
+	// Missing cookie
+	_, err = c.SignedCookie("missing", key)
+	assert.Equal(t, http.ErrNoCookie, err)
+}
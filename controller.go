@@ -0,0 +1,88 @@
+package akita
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ControllerRoutes is implemented by the controllers registered with
+// `Akita#RegisterController()` which choose the paths of their routes.
+type ControllerRoutes interface {
+	// RoutePath returns the path, relative to the prefix, of the route of the
+	// handler method name, e.g. "/users/:id" for "GetUser", or "" for the
+	// default one.
+	RoutePath(name string) string
+}
+
+// RegisterController registers the methods of ctrl with the signature
+// `func(Context) error` and a name starting with an HTTP method, e.g.
+// `GetUsers` or `PostUsers`, as the routes for that method and the rest of the
+// name in kebab case under prefix, here `<prefix>/users`. A method named after
+// the HTTP method alone, e.g. `Get`, is routed to prefix. Controllers implement
+// `ControllerRoutes` to choose other paths, e.g. with path parameters. The
+// routes are named after the type and method, e.g. "UsersController.GetUsers",
+// and have the optional route-level middleware.
+func (a *Akita) RegisterController(prefix string, ctrl interface{}, m ...MiddlewareFunc) []*Route {
+	v := reflect.ValueOf(ctrl)
+	t := v.Type()
+	typeName := reflect.Indirect(v).Type().Name()
+	custom, _ := ctrl.(ControllerRoutes)
+	routes := []*Route{}
+	for i := 0; i < t.NumMethod(); i++ {
+		name := t.Method(i).Name
+		h, ok := v.Method(i).Interface().(func(Context) error)
+		if !ok {
+			continue
+		}
+		method, rest := controllerMethod(name)
+		if method == "" {
+			continue
+		}
+		path := ""
+		if custom != nil {
+			path = custom.RoutePath(name)
+		}
+		if path == "" && rest != "" {
+			path = "/" + kebabCase(rest)
+		}
+		r := a.Add(method, prefix+path, h, m...)
+		r.Name = typeName + "." + name
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// controllerMethod splits the name of a controller method into the HTTP method
+// it starts with, if any, and the rest.
+func controllerMethod(name string) (string, string) {
+	for _, m := range methods {
+		prefix := m[:1] + strings.ToLower(m[1:])
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := name[len(prefix):]
+		// E.g. `Posting` is not `POST` with `ing`
+		if rest != "" && !unicode.IsUpper(rune(rest[0])) {
+			continue
+		}
+		return m, rest
+	}
+	return "", ""
+}
+
+// kebabCase turns a camel case name into kebab case, e.g. `UserAPIKeys` into
+// `user-api-keys`.
+func kebabCase(name string) string {
+	runes := []rune(name)
+	buf := new(bytes.Buffer)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			buf.WriteByte('-')
+		}
+		buf.WriteRune(unicode.ToLower(r))
+	}
+	return buf.String()
+}
@@ -0,0 +1,79 @@
+package akita
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	usersController struct {
+		prefix string
+	}
+
+	itemsController struct{}
+)
+
+func (c *usersController) Get(ctx Context) error {
+	return ctx.String(http.StatusOK, c.prefix+"index")
+}
+
+func (c *usersController) GetUsers(ctx Context) error {
+	return ctx.String(http.StatusOK, c.prefix+"list")
+}
+
+func (c *usersController) PostUsers(ctx Context) error {
+	return ctx.String(http.StatusCreated, c.prefix+"create")
+}
+
+func (c *usersController) DeleteUserAPIKeys(ctx Context) error {
+	return ctx.String(http.StatusOK, c.prefix+"delete keys")
+}
+
+// Not handlers
+func (c *usersController) Posting(ctx Context) error { return nil }
+func (c *usersController) GetName() string           { return "users" }
+func (c *usersController) Users(ctx Context) error   { return nil }
+
+func (itemsController) GetItem(ctx Context) error {
+	return ctx.String(http.StatusOK, "item "+ctx.Param("id"))
+}
+
+func (itemsController) RoutePath(name string) string {
+	if name == "GetItem" {
+		return "/:id"
+	}
+	return ""
+}
+
+func TestAkitaRegisterController(t *testing.T) {
+	a := New()
+	routes := a.RegisterController("/api", &usersController{prefix: "users "})
+	a.RegisterController("/items", itemsController{})
+	if assert.Len(t, routes, 4) {
+		assert.Equal(t, "usersController.DeleteUserAPIKeys", routes[0].Name)
+		assert.Equal(t, DELETE, routes[0].Method)
+		assert.Equal(t, "/api/user-api-keys", routes[0].Path)
+	}
+
+	c, b := request(GET, "/api", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "users index", b)
+	c, b = request(GET, "/api/users", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "users list", b)
+	c, b = request(POST, "/api/users", a)
+	assert.Equal(t, http.StatusCreated, c)
+	assert.Equal(t, "users create", b)
+	c, _ = request(DELETE, "/api/user-api-keys", a)
+	assert.Equal(t, http.StatusOK, c)
+	c, _ = request(POST, "/api/ing", a)
+	assert.Equal(t, http.StatusNotFound, c)
+
+	// Custom paths
+	c, b = request(GET, "/items/42", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "item 42", b)
+	assert.Equal(t, "/items/42", a.Reverse("itemsController.GetItem", "42"))
+}
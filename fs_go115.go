@@ -0,0 +1,8 @@
+//go:build !go1.16
+// +build !go1.16
+
+package akita
+
+type (
+	fsContext interface{}
+)
@@ -0,0 +1,81 @@
+//go:build go1.16
+// +build go1.16
+
+package akita
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+type (
+	fsContext interface {
+		// FileFS sends a response with the content of the file from the
+		// filesystem, e.g. one embedded with `//go:embed`.
+		FileFS(file string, fsys fs.FS) error
+	}
+)
+
+// StaticFS registers a new route with path prefix to serve static files from
+// the provided filesystem, e.g. one embedded with `//go:embed`.
+func (a *Akita) StaticFS(prefix string, fsys fs.FS) *Route {
+	return staticFS(a, prefix, fsys)
+}
+
+// StaticFS implements `Akita#StaticFS()` for sub-routes within the Group.
+func (g *Group) StaticFS(prefix string, fsys fs.FS) {
+	staticFS(g, prefix, fsys)
+}
+
+func staticFS(i i, prefix string, fsys fs.FS) *Route {
+	h := func(c Context) error {
+		p, err := PathUnescape(c.Param("*"))
+		if err != nil {
+			return err
+		}
+		return c.FileFS(p, fsys)
+	}
+	i.GET(prefix, h)
+	if prefix == "/" {
+		return i.GET(prefix+"*", h)
+	}
+
+	return i.GET(prefix+"/*", h)
+}
+
+func (ctx *context) FileFS(file string, fsys fs.FS) (err error) {
+	// `fs.FS` names are unrooted, "/"+ for security
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+	if file == "" {
+		file = "."
+	}
+	f, err := fsys.Open(file)
+	if err != nil {
+		return ctx.akita.notFoundHandler(ctx)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return
+	}
+	if fi.IsDir() {
+		file = path.Join(file, indexPage)
+		f, err = fsys.Open(file)
+		if err != nil {
+			return ctx.akita.notFoundHandler(ctx)
+		}
+		defer f.Close()
+		if fi, err = f.Stat(); err != nil {
+			return
+		}
+	}
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		return errors.New("akita: file does not implement io.ReadSeeker")
+	}
+	return ctx.ServeReader(fi.Name(), fi.ModTime(), rs)
+}
@@ -0,0 +1,73 @@
+//go:build go1.16
+// +build go1.16
+
+package akita
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFS = fstest.MapFS{
+	"index.html":       {Data: []byte("<h1>Akita</h1>")},
+	"css/main.css":     {Data: []byte("body{}")},
+	"docs/index.html":  {Data: []byte("<h1>Docs</h1>")},
+	"docs/readme.text": {Data: []byte("readme")},
+}
+
+func TestContextFileFS(t *testing.T) {
+	a := New()
+
+	// File
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	c := a.NewContext(req, rec)
+	if assert.NoError(t, c.FileFS("css/main.css", testFS)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "body{}", rec.Body.String())
+	}
+
+	// Directory index
+	rec = httptest.NewRecorder()
+	c = a.NewContext(req, rec)
+	if assert.NoError(t, c.FileFS("/docs", testFS)) {
+		assert.Equal(t, "<h1>Docs</h1>", rec.Body.String())
+	}
+
+	// Not found
+	rec = httptest.NewRecorder()
+	c = a.NewContext(req, rec)
+	err := c.FileFS("missing.txt", testFS)
+	if assert.IsType(t, new(HTTPError), err) {
+		assert.Equal(t, http.StatusNotFound, err.(*HTTPError).Code)
+	}
+}
+
+func TestAkitaStaticFS(t *testing.T) {
+	a := New()
+	a.StaticFS("/static", testFS)
+	a.Group("/g").StaticFS("/assets", testFS)
+
+	c, b := request(GET, "/static/css/main.css", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "body{}", b)
+
+	c, b = request(GET, "/static", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "<h1>Akita</h1>", b)
+
+	c, b = request(GET, "/g/assets/docs/", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "<h1>Docs</h1>", b)
+
+	// No escaping the filesystem
+	c, _ = request(GET, "/static/../fs_go116.go", a)
+	assert.Equal(t, http.StatusNotFound, c)
+
+	c, _ = request(GET, "/static/missing.txt", a)
+	assert.Equal(t, http.StatusNotFound, c)
+}
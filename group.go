@@ -9,20 +9,85 @@ type (
 	// routes that share a common middleware or functionality that should be separate
 	// from the parent akita instance while still inheriting from it.
 	Group struct {
-		prefix     string
-		middleware []MiddlewareFunc
-		akita      *Akita
+		host         string
+		prefix       string
+		middleware   []MiddlewareFunc
+		akita        *Akita
+		parent       *Group
+		errorHandler HTTPErrorHandler
+		noCatchAll   bool
+		catchAll     []*Route
 	}
 )
 
 // Use implements `Akita#Use()` for sub-routes within the Group.
 func (g *Group) Use(middleware ...MiddlewareFunc) {
 	g.middleware = append(g.middleware, middleware...)
+	if g.noCatchAll {
+		return
+	}
 	// Allow all requests to reach the group as they might get dropped if router
 	// doesn't find a match, making none of the group middleware process.
-	g.akita.Any(path.Clean(g.prefix+"/*"), func(c Context) error {
-		return NotFoundHandler(c)
-	}, g.middleware...)
+	m := []MiddlewareFunc{g.useErrorHandler}
+	m = append(m, g.middleware...)
+	g.catchAll = g.catchAll[:0]
+	for _, method := range methods {
+		g.catchAll = append(g.catchAll, g.akita.add(g.host, method, g.catchAllPath(), func(c Context) error {
+			return g.akita.notFoundHandler(c)
+		}, m...))
+	}
+}
+
+// DisableCatchAll stops the Group from handling the requests under its prefix
+// which don't match any of its routes. They are routed as if the Group didn't
+// exist, e.g. to a broader `/*` route or the not found handler of the instance,
+// without running the group middleware, and the catch-all routes are removed
+// from the router and `Akita#Routes()`. Sub-groups created afterwards
+// inherit the setting.
+func (g *Group) DisableCatchAll() {
+	g.noCatchAll = true
+	router := g.akita.router
+	if g.host != "" {
+		router = g.akita.routers[g.host]
+	}
+	if router == nil {
+		return
+	}
+	// Routes registered for the path by the application stay
+	for _, r := range g.catchAll {
+		if router.routes[r.Method+r.Path] == r {
+			delete(router.routes, r.Method+r.Path)
+			router.remove(r.Method, r.Path)
+		}
+	}
+	g.catchAll = nil
+}
+
+func (g *Group) catchAllPath() string {
+	return path.Clean(g.prefix + "/*")
+}
+
+// SetHTTPErrorHandler sets the HTTP error handler for the routes registered
+// within the Group and its sub-groups, taking precedence over
+// `Akita#HTTPErrorHandler`.
+func (g *Group) SetHTTPErrorHandler(h HTTPErrorHandler) {
+	g.errorHandler = h
+}
+
+// useErrorHandler is the middleware selecting the error handler of the Group,
+// or of its closest parent having one, for the request.
+func (g *Group) useErrorHandler(next HandlerFunc) HandlerFunc {
+	return func(ctx Context) error {
+		for sg := g; sg != nil; sg = sg.parent {
+			if sg.errorHandler != nil {
+				if c, ok := ctx.(*context); ok {
+					c.errorHandler = sg.errorHandler
+				}
+				break
+			}
+		}
+		return next(ctx)
+	}
 }
 
 // CONNECT implements `Akita#CONNECT()` for sub-routes within the Group.
@@ -84,12 +149,28 @@ func (g *Group) Match(methods []string, path string, handler HandlerFunc, middle
 	}
 }
 
+// AddPaths implements `Akita#AddPaths()` for sub-routes within the Group.
+func (g *Group) AddPaths(method string, paths []string, handler HandlerFunc, middleware ...MiddlewareFunc) []*Route {
+	routes := make([]*Route, 0, len(paths))
+	for _, p := range paths {
+		routes = append(routes, g.Add(method, p, handler, middleware...))
+	}
+	return routes
+}
+
+// Except implements `Akita#Except()` for sub-routes within the Group.
+func (g *Group) Except(excluded []string, path string, handler HandlerFunc, middleware ...MiddlewareFunc) {
+	g.Match(exceptMethods(excluded), path, handler, middleware...)
+}
+
 // Group creates a new sub-group with prefix and optional sub-group-level middleware.
 func (g *Group) Group(prefix string, middleware ...MiddlewareFunc) *Group {
 	m := []MiddlewareFunc{}
 	m = append(m, g.middleware...)
 	m = append(m, middleware...)
-	return g.akita.Group(g.prefix+prefix, m...)
+	sg := &Group{host: g.host, prefix: g.prefix + prefix, akita: g.akita, parent: g, noCatchAll: g.noCatchAll}
+	sg.Use(m...)
+	return sg
 }
 
 // Static implements `Akita#Static()` for sub-routes within the Group.
@@ -99,7 +180,9 @@ func (g *Group) Static(prefix, root string) {
 
 // File implements `Akita#File()` for sub-routes within the Group.
 func (g *Group) File(path, file string) {
-	g.akita.File(g.prefix+path, file)
+	g.akita.add(g.host, GET, g.prefix+path, func(ctx Context) error {
+		return ctx.File(file)
+	})
 }
 
 // Add implements `Akita#Add()` for sub-routes within the Group.
@@ -107,8 +190,8 @@ func (g *Group) Add(method, path string, handler HandlerFunc, middleware ...Midd
 	// Combine into a new slice to avoid accidentally passing the same slice for
 	// multiple routes, which would lead to later add() calls overwriting the
 	// middleware from earlier calls.
-	m := []MiddlewareFunc{}
+	m := []MiddlewareFunc{g.useErrorHandler}
 	m = append(m, g.middleware...)
 	m = append(m, middleware...)
-	return g.akita.Add(method, g.prefix+path, handler, m...)
+	return g.akita.add(g.host, method, g.prefix+path, handler, m...)
 }
@@ -1,6 +1,9 @@
 package akita
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,6 +24,7 @@ func TestGroup(t *testing.T) {
 	g.TRACE("/", h)
 	g.Any("/", h)
 	g.Match([]string{GET, POST}, "/", h)
+	g.Except([]string{TRACE}, "/", h)
 	g.Static("/static", "/tmp")
 	g.File("/walle", "_fixture/images//walle.png")
 }
@@ -64,3 +68,191 @@ func TestGroupRouteMiddleware(t *testing.T) {
 	c, _ = request(GET, "/group/405", e)
 	assert.Equal(t, 405, c)
 }
+
+func TestGroupMiddlewareOrder(t *testing.T) {
+	e := New()
+	buf := new(bytes.Buffer)
+	mw := func(s string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				buf.WriteString(s + " ")
+				return next(c)
+			}
+		}
+	}
+	e.Pre(mw("pre"))
+	e.Use(mw("global"))
+	g := e.Group("/group", mw("group1"))
+	g.Use(mw("group2"))
+	route := g.GET("/users", func(c Context) error {
+		buf.WriteString("handler")
+		return c.NoContent(http.StatusOK)
+	}, mw("route1"), mw("route2"))
+
+	// Group and route middleware are stored on the route
+	assert.Len(t, route.Middleware, 5) // With the group error handler
+
+	c, _ := request(GET, "/group/users", e)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "pre global group1 group2 route1 route2 handler", buf.String())
+
+	// Unmatched paths under the group run the group middleware only
+	buf.Reset()
+	c, _ = request(GET, "/group/unknown", e)
+	assert.Equal(t, http.StatusNotFound, c)
+	assert.Equal(t, "pre global group1 group2 ", buf.String())
+}
+
+func TestGroupMiddlewareRelativePath(t *testing.T) {
+	e := New()
+	buf := new(bytes.Buffer)
+	mw := func(s string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				buf.WriteString(s + " ")
+				return next(c)
+			}
+		}
+	}
+	h := func(c Context) error {
+		if c.Route() == nil {
+			return ErrRouteNotFound
+		}
+		buf.WriteString(c.Route().Path)
+		return c.NoContent(http.StatusOK)
+	}
+	assert.Equal(t, "/users", e.GET("users", h, mw("route")).Path)
+	g := e.Group("", mw("group"))
+	g.GET("accounts", h, mw("route"))
+
+	c, _ := request(GET, "/users", e)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "route /users", buf.String())
+
+	buf.Reset()
+	c, _ = request(GET, "/accounts", e)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "group route /accounts", buf.String())
+	assert.NotNil(t, e.FindRoute("", GET, "/accounts"))
+}
+
+func TestGroupHTTPErrorHandler(t *testing.T) {
+	a := New()
+	h := func(Context) error {
+		return ErrForbidden
+	}
+	a.GET("/", h)
+	v1 := a.Group("/v1")
+	v1.SetHTTPErrorHandler(func(err error, c Context) {
+		c.String(err.(*HTTPError).Code, "v1: "+err.Error())
+	})
+	v1.GET("/", h)
+	v1.Group("/admin").GET("/", h)
+	v2 := a.Group("/v2")
+	v2.GET("/", h)
+	v2.SetHTTPErrorHandler(func(err error, c Context) {
+		c.JSON(err.(*HTTPError).Code, Map{"error": Map{"message": err.(*HTTPError).Message}})
+	})
+
+	c, b := request(GET, "/v1/", a)
+	assert.Equal(t, http.StatusForbidden, c)
+	assert.Equal(t, "v1: code=403, message=Forbidden", b)
+
+	c, b = request(GET, "/v1/admin/", a)
+	assert.Equal(t, http.StatusForbidden, c)
+	assert.Equal(t, "v1: code=403, message=Forbidden", b)
+
+	c, b = request(GET, "/v2/", a)
+	assert.Equal(t, http.StatusForbidden, c)
+	assert.Equal(t, `{"error":{"message":"Forbidden"}}`, b)
+
+	c, b = request(GET, "/", a)
+	assert.Equal(t, http.StatusForbidden, c)
+	assert.Equal(t, `{"message":"Forbidden"}`, b)
+
+	// Unmatched routes within the group
+	c, b = request(GET, "/v1/missing", a)
+	assert.Equal(t, http.StatusNotFound, c)
+	assert.Equal(t, "v1: code=404, message=Not Found", b)
+}
+
+func TestGroupCatchAll(t *testing.T) {
+	mw := func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.Response().Header().Set("X-Group", "yes")
+			return next(ctx)
+		}
+	}
+	h := func(ctx Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	}
+	hasCatchAll := func(a *Akita, prefix string) bool {
+		for _, r := range a.Routes() {
+			if r.Path == prefix+"/*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Enabled
+	a := New()
+	a.Group("/g", mw).GET("/users", h)
+	req := httptest.NewRequest(GET, "/g/missing", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "yes", rec.Header().Get("X-Group"))
+	assert.True(t, hasCatchAll(a, "/g"))
+
+	// Disabled
+	a = New()
+	g := a.Group("/g", mw)
+	g.DisableCatchAll()
+	g.GET("/users", h)
+	g.Group("/admin", mw)
+	req = httptest.NewRequest(GET, "/g/missing", nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("X-Group"))
+	assert.False(t, hasCatchAll(a, "/g"))
+	assert.False(t, hasCatchAll(a, "/g/admin"))
+
+	c, b := request(GET, "/g/users", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "OK", b)
+
+	// A wildcard route of the application keeps the group middleware
+	a = New()
+	g = a.Group("/g", mw)
+	g.GET("/*", h)
+	g.DisableCatchAll()
+	req = httptest.NewRequest(GET, "/g/anything", nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "yes", rec.Header().Get("X-Group"))
+
+	// A broader wildcard route of the application catches the requests
+	a = New()
+	a.GET("/*", func(c Context) error {
+		return c.String(http.StatusOK, "spa")
+	})
+	g = a.Group("/g", mw)
+	g.GET("/users", h)
+	g.DisableCatchAll()
+	p := a.Group("/posts/:id", mw)
+	p.DisableCatchAll()
+	for _, path := range []string{"/g/missing", "/g", "/posts/1/missing"} {
+		req = httptest.NewRequest(GET, path, nil)
+		rec = httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, "spa", rec.Body.String(), path)
+		assert.Empty(t, rec.Header().Get("X-Group"), path)
+	}
+	c, b = request(GET, "/g/users", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Equal(t, "OK", b)
+}
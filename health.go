@@ -0,0 +1,97 @@
+package akita
+
+import (
+	stdContext "context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// HealthCheck reports the health of a dependency, e.g. a database. It should
+	// return early when ctx is done.
+	HealthCheck func(ctx stdContext.Context) error
+
+	// HealthCheckResult is the status of a single check in the response of a
+	// health check endpoint.
+	HealthCheckResult struct {
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	// HealthCheckResponse is the response body of a health check endpoint. Checks
+	// are listed in the order they were registered.
+	HealthCheckResponse struct {
+		Status string              `json:"status"`
+		Checks []HealthCheckResult `json:"checks"`
+	}
+)
+
+// HealthCheckTimeout is the time all checks of a health check endpoint have to
+// complete before they are reported as failed.
+var HealthCheckTimeout = 5 * time.Second
+
+// ErrHealthCheckTimeout is reported for checks which didn't complete within
+// `HealthCheckTimeout`.
+var ErrHealthCheckTimeout = NewHTTPError(http.StatusServiceUnavailable, "health check timed out")
+
+// PingCheck always succeeds. Use it for liveness endpoints which only report
+// that the server is up.
+func PingCheck(stdContext.Context) error {
+	return nil
+}
+
+// AddHealthCheck registers a GET route for path which runs the checks
+// concurrently. It responds with 200 if all of them succeed or 503 otherwise,
+// with a `HealthCheckResponse` as JSON body.
+func (a *Akita) AddHealthCheck(path string, checks ...HealthCheck) *Route {
+	return a.GET(path, func(ctx Context) error {
+		c, cancel := stdContext.WithTimeout(ctx.Request().Context(), HealthCheckTimeout)
+		defer cancel()
+
+		res := HealthCheckResponse{
+			Status: "ok",
+			Checks: make([]HealthCheckResult, len(checks)),
+		}
+		wg := sync.WaitGroup{}
+		for i, check := range checks {
+			wg.Add(1)
+			go func(i int, check HealthCheck) {
+				defer wg.Done()
+				done := make(chan error, 1)
+				go func() {
+					done <- check(c)
+				}()
+				var err error
+				select {
+				case err = <-done:
+				case <-c.Done():
+					err = ErrHealthCheckTimeout
+				}
+				res.Checks[i] = HealthCheckResult{Status: "ok"}
+				if err != nil {
+					res.Checks[i] = HealthCheckResult{Status: "fail", Error: healthCheckError(err)}
+				}
+			}(i, check)
+		}
+		wg.Wait()
+
+		code := http.StatusOK
+		for _, r := range res.Checks {
+			if r.Status != "ok" {
+				res.Status = "fail"
+				code = http.StatusServiceUnavailable
+			}
+		}
+		return ctx.JSON(code, res)
+	})
+}
+
+func healthCheckError(err error) string {
+	if he, ok := err.(*HTTPError); ok {
+		if msg, ok := he.Message.(string); ok {
+			return msg
+		}
+	}
+	return err.Error()
+}
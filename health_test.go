@@ -0,0 +1,59 @@
+package akita
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAkitaHealthCheck(t *testing.T) {
+	a := New()
+	a.AddHealthCheck("/healthz", PingCheck, func(stdContext.Context) error {
+		return nil
+	})
+
+	c, b := request(GET, "/healthz", a)
+	assert.Equal(t, http.StatusOK, c)
+	res := HealthCheckResponse{}
+	if assert.NoError(t, json.Unmarshal([]byte(b), &res)) {
+		assert.Equal(t, "ok", res.Status)
+		assert.Equal(t, []HealthCheckResult{{Status: "ok"}, {Status: "ok"}}, res.Checks)
+	}
+}
+
+func TestAkitaHealthCheckFailing(t *testing.T) {
+	a := New()
+	a.AddHealthCheck("/readyz", PingCheck, func(stdContext.Context) error {
+		return errors.New("database unreachable")
+	})
+
+	c, b := request(GET, "/readyz", a)
+	assert.Equal(t, http.StatusServiceUnavailable, c)
+	res := HealthCheckResponse{}
+	if assert.NoError(t, json.Unmarshal([]byte(b), &res)) {
+		assert.Equal(t, "fail", res.Status)
+		assert.Equal(t, HealthCheckResult{Status: "ok"}, res.Checks[0])
+		assert.Equal(t, HealthCheckResult{Status: "fail", Error: "database unreachable"}, res.Checks[1])
+	}
+}
+
+func TestAkitaHealthCheckTimeout(t *testing.T) {
+	timeout := HealthCheckTimeout
+	HealthCheckTimeout = 10 * time.Millisecond
+	defer func() { HealthCheckTimeout = timeout }()
+
+	a := New()
+	a.AddHealthCheck("/readyz", func(stdContext.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	c, b := request(GET, "/readyz", a)
+	assert.Equal(t, http.StatusServiceUnavailable, c)
+	assert.Contains(t, b, "health check timed out")
+}
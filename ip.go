@@ -0,0 +1,84 @@
+package akita
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPExtractor is a function to extract the client IP address from
+// `*http.Request`. Set `Akita#IPExtractor` to choose how `Context#RealIP()`
+// behaves.
+type IPExtractor func(*http.Request) string
+
+// ExtractIPDirect extracts the IP address from the network connection. Use it
+// when clients connect to the server directly, without any proxy in between.
+func ExtractIPDirect() IPExtractor {
+	return extractIP
+}
+
+// ExtractIPFromRealIPHeader extracts the IP address from the `X-Real-IP`
+// header. The header is only trusted when the request comes from one of the
+// trusted ranges, otherwise the direct IP address is returned.
+func ExtractIPFromRealIPHeader(trustedRanges ...net.IPNet) IPExtractor {
+	return func(req *http.Request) string {
+		directIP := extractIP(req)
+		if !isTrustedIP(directIP, trustedRanges) {
+			return directIP
+		}
+		if ip := strings.TrimSpace(req.Header.Get(HeaderXRealIP)); net.ParseIP(ip) != nil {
+			return ip
+		}
+		return directIP
+	}
+}
+
+// ExtractIPFromXFFHeader extracts the IP address from the `X-Forwarded-For`
+// header. The list is walked right-to-left, skipping the trusted proxies, and
+// the first untrusted address is returned. The header is ignored unless the
+// request comes from one of the trusted ranges.
+func ExtractIPFromXFFHeader(trustedRanges ...net.IPNet) IPExtractor {
+	return func(req *http.Request) string {
+		directIP := extractIP(req)
+		if !isTrustedIP(directIP, trustedRanges) {
+			return directIP
+		}
+		xffs := req.Header[HeaderXForwardedFor]
+		if len(xffs) == 0 {
+			return directIP
+		}
+		ips := strings.Split(strings.Join(xffs, ","), ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if net.ParseIP(ip) == nil {
+				// Malformed list, don't trust anything left of it
+				return directIP
+			}
+			if !isTrustedIP(ip, trustedRanges) || i == 0 {
+				return ip
+			}
+		}
+		return directIP
+	}
+}
+
+func extractIP(req *http.Request) string {
+	ra, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return ra
+}
+
+func isTrustedIP(ip string, trustedRanges []net.IPNet) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, r := range trustedRanges {
+		if r.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
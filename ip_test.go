@@ -0,0 +1,84 @@
+package akita
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseCIDR(s string) net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return *n
+}
+
+func TestExtractIPDirect(t *testing.T) {
+	req := httptest.NewRequest(GET, "/", nil)
+	req.RemoteAddr = "203.0.113.1:8080"
+	req.Header.Set(HeaderXForwardedFor, "127.0.0.1")
+	req.Header.Set(HeaderXRealIP, "127.0.0.1")
+	assert.Equal(t, "203.0.113.1", ExtractIPDirect()(req))
+}
+
+func TestExtractIPFromRealIPHeader(t *testing.T) {
+	extract := ExtractIPFromRealIPHeader(mustParseCIDR("10.0.0.0/8"))
+
+	// Spoofed header from an untrusted client
+	req := httptest.NewRequest(GET, "/", nil)
+	req.RemoteAddr = "203.0.113.1:8080"
+	req.Header.Set(HeaderXRealIP, "127.0.0.1")
+	assert.Equal(t, "203.0.113.1", extract(req))
+
+	// Trusted proxy
+	req.RemoteAddr = "10.0.0.1:8080"
+	req.Header.Set(HeaderXRealIP, "198.51.100.7")
+	assert.Equal(t, "198.51.100.7", extract(req))
+
+	// Trusted proxy with a malformed header
+	req.Header.Set(HeaderXRealIP, "bogus")
+	assert.Equal(t, "10.0.0.1", extract(req))
+}
+
+func TestExtractIPFromXFFHeader(t *testing.T) {
+	extract := ExtractIPFromXFFHeader(mustParseCIDR("10.0.0.0/8"))
+
+	// Spoofed header from an untrusted client
+	req := httptest.NewRequest(GET, "/", nil)
+	req.RemoteAddr = "203.0.113.1:8080"
+	req.Header.Set(HeaderXForwardedFor, "127.0.0.1")
+	assert.Equal(t, "203.0.113.1", extract(req))
+
+	// Client spoofing the left-most entry behind trusted proxies
+	req.RemoteAddr = "10.0.0.1:8080"
+	req.Header.Set(HeaderXForwardedFor, "127.0.0.1, 198.51.100.7, 10.0.0.2")
+	assert.Equal(t, "198.51.100.7", extract(req))
+
+	// Multiple headers
+	req.Header.Set(HeaderXForwardedFor, "127.0.0.1")
+	req.Header.Add(HeaderXForwardedFor, "198.51.100.7,10.0.0.2")
+	assert.Equal(t, "198.51.100.7", extract(req))
+
+	// Only trusted proxies
+	req.Header.Set(HeaderXForwardedFor, "10.0.0.3, 10.0.0.2")
+	assert.Equal(t, "10.0.0.3", extract(req))
+
+	// Malformed entry
+	req.Header.Set(HeaderXForwardedFor, "198.51.100.7, bogus")
+	assert.Equal(t, "10.0.0.1", extract(req))
+}
+
+func TestContextRealIPExtractor(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	req.RemoteAddr = "203.0.113.1:8080"
+	req.Header.Set(HeaderXForwardedFor, "127.0.0.1")
+	c := a.NewContext(req, httptest.NewRecorder())
+	assert.Equal(t, "127.0.0.1", c.RealIP())
+
+	a.IPExtractor = ExtractIPFromXFFHeader(mustParseCIDR("10.0.0.0/8"))
+	assert.Equal(t, "203.0.113.1", c.RealIP())
+}
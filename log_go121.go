@@ -0,0 +1,203 @@
+//go:build go1.21
+// +build go1.21
+
+package akita
+
+import (
+	stdContext "context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/itchenyi/common/log"
+)
+
+type (
+	// slogLogger is the `Logger` delegating to a `*slog.Logger`.
+	slogLogger struct {
+		logger *slog.Logger
+		prefix string
+		level  log.Lvl
+	}
+
+	// slogWriter is the `Logger#Output()` of slogLogger, each write is
+	// logged as a message at the info level.
+	slogWriter struct {
+		logger *slogLogger
+	}
+)
+
+// NewSlogLogger returns a `Logger` delegating to l, to be set as
+// `Akita#Logger`. The output is the one of the slog handler, `SetOutput()` is
+// a no-op and `Output()` returns a writer logging each write as a message.
+// The `Printj()` family logs the JSON fields as attributes. The prefix, if
+// any, is added as the "prefix" attribute.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{logger: l, level: log.DEBUG}
+}
+
+func (l *slogLogger) Output() io.Writer {
+	return &slogWriter{logger: l}
+}
+
+func (l *slogLogger) SetOutput(w io.Writer) {}
+
+func (l *slogLogger) Prefix() string {
+	return l.prefix
+}
+
+func (l *slogLogger) SetPrefix(p string) {
+	l.prefix = p
+}
+
+func (l *slogLogger) Level() log.Lvl {
+	return l.level
+}
+
+func (l *slogLogger) SetLevel(v log.Lvl) {
+	l.level = v
+}
+
+func (l *slogLogger) Print(i ...interface{}) {
+	l.log(slog.LevelInfo, fmt.Sprint(i...), nil)
+}
+
+func (l *slogLogger) Printf(format string, args ...interface{}) {
+	l.log(slog.LevelInfo, fmt.Sprintf(format, args...), nil)
+}
+
+func (l *slogLogger) Printj(j log.JSON) {
+	l.log(slog.LevelInfo, "", j)
+}
+
+func (l *slogLogger) Debug(i ...interface{}) {
+	if l.enabled(log.DEBUG) {
+		l.log(slog.LevelDebug, fmt.Sprint(i...), nil)
+	}
+}
+
+func (l *slogLogger) Debugf(format string, args ...interface{}) {
+	if l.enabled(log.DEBUG) {
+		l.log(slog.LevelDebug, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+func (l *slogLogger) Debugj(j log.JSON) {
+	if l.enabled(log.DEBUG) {
+		l.log(slog.LevelDebug, "", j)
+	}
+}
+
+func (l *slogLogger) Info(i ...interface{}) {
+	if l.enabled(log.INFO) {
+		l.log(slog.LevelInfo, fmt.Sprint(i...), nil)
+	}
+}
+
+func (l *slogLogger) Infof(format string, args ...interface{}) {
+	if l.enabled(log.INFO) {
+		l.log(slog.LevelInfo, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+func (l *slogLogger) Infoj(j log.JSON) {
+	if l.enabled(log.INFO) {
+		l.log(slog.LevelInfo, "", j)
+	}
+}
+
+func (l *slogLogger) Warn(i ...interface{}) {
+	if l.enabled(log.WARN) {
+		l.log(slog.LevelWarn, fmt.Sprint(i...), nil)
+	}
+}
+
+func (l *slogLogger) Warnf(format string, args ...interface{}) {
+	if l.enabled(log.WARN) {
+		l.log(slog.LevelWarn, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+func (l *slogLogger) Warnj(j log.JSON) {
+	if l.enabled(log.WARN) {
+		l.log(slog.LevelWarn, "", j)
+	}
+}
+
+func (l *slogLogger) Error(i ...interface{}) {
+	if l.enabled(log.ERROR) {
+		l.log(slog.LevelError, fmt.Sprint(i...), nil)
+	}
+}
+
+func (l *slogLogger) Errorf(format string, args ...interface{}) {
+	if l.enabled(log.ERROR) {
+		l.log(slog.LevelError, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+func (l *slogLogger) Errorj(j log.JSON) {
+	if l.enabled(log.ERROR) {
+		l.log(slog.LevelError, "", j)
+	}
+}
+
+func (l *slogLogger) Fatal(i ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprint(i...), nil)
+	os.Exit(1)
+}
+
+func (l *slogLogger) Fatalf(format string, args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprintf(format, args...), nil)
+	os.Exit(1)
+}
+
+func (l *slogLogger) Fatalj(j log.JSON) {
+	l.log(slog.LevelError, "", j)
+	os.Exit(1)
+}
+
+func (l *slogLogger) Panic(i ...interface{}) {
+	msg := fmt.Sprint(i...)
+	l.log(slog.LevelError, msg, nil)
+	panic(msg)
+}
+
+func (l *slogLogger) Panicf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	l.log(slog.LevelError, msg, nil)
+	panic(msg)
+}
+
+func (l *slogLogger) Panicj(j log.JSON) {
+	l.log(slog.LevelError, "", j)
+	panic(j)
+}
+
+func (l *slogLogger) enabled(v log.Lvl) bool {
+	return l.level <= v
+}
+
+func (l *slogLogger) log(level slog.Level, msg string, j log.JSON) {
+	attrs := make([]slog.Attr, 0, len(j)+1)
+	if l.prefix != "" {
+		attrs = append(attrs, slog.String("prefix", l.prefix))
+	}
+	keys := make([]string, 0, len(j))
+	for k := range j {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, j[k]))
+	}
+	l.logger.LogAttrs(stdContext.Background(), level, msg, attrs...)
+}
+
+func (w *slogWriter) Write(b []byte) (int, error) {
+	w.logger.log(slog.LevelInfo, strings.TrimSuffix(string(b), "\n"), nil)
+	return len(b), nil
+}
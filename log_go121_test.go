@@ -0,0 +1,45 @@
+//go:build go1.21
+// +build go1.21
+
+package akita
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/itchenyi/common/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := NewSlogLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	l.SetPrefix("akita")
+	l.SetLevel(log.INFO)
+
+	l.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	l.Warnf("disk at %d%%", 90)
+	record := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "disk at 90%", record["msg"])
+	assert.Equal(t, "akita", record["prefix"])
+
+	buf.Reset()
+	l.Errorj(log.JSON{"user": "jon", "code": 42})
+	record = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "jon", record["user"])
+	assert.Equal(t, float64(42), record["code"])
+
+	buf.Reset()
+	l.Output().Write([]byte("http: TLS handshake error\n"))
+	record = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "http: TLS handshake error", record["msg"])
+}
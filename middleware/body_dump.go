@@ -3,9 +3,9 @@ package middleware
 import (
 	"bufio"
 	"bytes"
-	"io/ioutil"
 	"net"
 	"net/http"
+	"time"
 
 	"io"
 
@@ -19,13 +19,39 @@ type (
 		Skipper Skipper
 
 		// Handler receives request and response payload.
-		// Required.
+		// Required, unless HandlerWithMeta is set.
 		Handler BodyDumpHandler
+
+		// HandlerWithMeta receives request and response payload along with
+		// metadata of the response. It is called instead of Handler if set.
+		// Optional.
+		HandlerWithMeta BodyDumpHandlerMeta
 	}
 
 	// BodyDumpHandler receives the request and response payload.
 	BodyDumpHandler func(akita.Context, []byte, []byte)
 
+	// BodyDumpHandlerMeta receives the request and response payload and the
+	// response metadata.
+	BodyDumpHandlerMeta func(akita.Context, []byte, []byte, BodyDumpMeta)
+
+	// BodyDumpMeta describes the dumped response.
+	BodyDumpMeta struct {
+		// Status is the response status code.
+		Status int
+
+		// Latency is the time taken by the next handlers.
+		Latency time.Duration
+
+		// ResponseSize is the number of bytes written by the handler.
+		ResponseSize int64
+
+		// DumpedSize is the number of bytes which went through the middleware.
+		// It is smaller than ResponseSize if the response is compressed by a
+		// middleware running after BodyDump, e.g. Gzip.
+		DumpedSize int64
+	}
+
 	bodyDumpResponseWriter struct {
 		io.Writer
 		http.ResponseWriter
@@ -53,7 +79,7 @@ func BodyDump(handler BodyDumpHandler) akita.MiddlewareFunc {
 // See: `BodyDump()`.
 func BodyDumpWithConfig(config BodyDumpConfig) akita.MiddlewareFunc {
 	// Defaults
-	if config.Handler == nil {
+	if config.Handler == nil && config.HandlerWithMeta == nil {
 		panic("akita: body-dump middleware requires a handler function")
 	}
 	if config.Skipper == nil {
@@ -67,11 +93,8 @@ func BodyDumpWithConfig(config BodyDumpConfig) akita.MiddlewareFunc {
 			}
 
 			// Request
-			reqBody := []byte{}
-			if ctx.Request().Body != nil { // Read
-				reqBody, _ = ioutil.ReadAll(ctx.Request().Body)
-			}
-			ctx.Request().Body = ioutil.NopCloser(bytes.NewBuffer(reqBody)) // Reset
+			reqBody, _ := ctx.Body()
+			start := time.Now()
 
 			// Response
 			resBody := new(bytes.Buffer)
@@ -84,6 +107,15 @@ func BodyDumpWithConfig(config BodyDumpConfig) akita.MiddlewareFunc {
 			}
 
 			// Callback
+			if config.HandlerWithMeta != nil {
+				config.HandlerWithMeta(ctx, reqBody, resBody.Bytes(), BodyDumpMeta{
+					Status:       ctx.Response().Status,
+					Latency:      time.Since(start),
+					ResponseSize: ctx.Response().Size,
+					DumpedSize:   int64(resBody.Len()),
+				})
+				return
+			}
 			config.Handler(ctx, reqBody, resBody.Bytes())
 
 			return
@@ -107,6 +139,10 @@ func (w *bodyDumpResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return w.ResponseWriter.(http.Hijacker).Hijack()
 }
 
+func (w *bodyDumpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func (w *bodyDumpResponseWriter) CloseNotify() <-chan bool {
 	return w.ResponseWriter.(http.CloseNotifier).CloseNotify()
 }
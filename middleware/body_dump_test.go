@@ -38,3 +38,30 @@ func TestBodyDump(t *testing.T) {
 		assert.Equal(t, hw, rec.Body.String())
 	}
 }
+
+func TestBodyDumpMeta(t *testing.T) {
+	a := akita.New()
+	body := strings.Repeat("akita", 100)
+	h := func(ctx akita.Context) error {
+		return ctx.String(http.StatusCreated, body)
+	}
+
+	// Compressed after the dump
+	meta := BodyDumpMeta{}
+	mw := BodyDumpWithConfig(BodyDumpConfig{
+		HandlerWithMeta: func(c akita.Context, reqBody, resBody []byte, m BodyDumpMeta) {
+			meta = m
+		},
+	})
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	req.Header.Set(akita.HeaderAcceptEncoding, gzipScheme)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, mw(Gzip()(h))(ctx)) {
+		assert.Equal(t, http.StatusCreated, meta.Status)
+		assert.True(t, meta.Latency > 0)
+		assert.Equal(t, int64(len(body)), meta.ResponseSize)
+		assert.Equal(t, int64(rec.Body.Len()), meta.DumpedSize)
+		assert.True(t, meta.DumpedSize < meta.ResponseSize)
+	}
+}
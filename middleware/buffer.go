@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// BufferConfig defines the config for Buffer middleware.
+	BufferConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Limit is the maximum number of bytes buffered. Larger responses are
+		// sent as they are written, without the guarantee of the middleware.
+		// Optional. Default value 1MB.
+		Limit int `json:"limit"`
+	}
+
+	bufferedResponseWriter struct {
+		http.ResponseWriter
+		header      http.Header
+		code        int
+		buf         bytes.Buffer
+		limit       int
+		passthrough bool
+	}
+)
+
+var (
+	// DefaultBufferConfig is the default Buffer middleware config.
+	DefaultBufferConfig = BufferConfig{
+		Skipper: DefaultSkipper,
+		Limit:   1 << 20, // 1MB
+	}
+)
+
+// Buffer returns a middleware which holds the response back until the handler
+// returns. If it returns an error the buffered status, headers and body are
+// discarded so the error handler sends a clean response rather than the client
+// getting a truncated one.
+func Buffer() akita.MiddlewareFunc {
+	return BufferWithConfig(DefaultBufferConfig)
+}
+
+// BufferWithConfig returns a Buffer middleware with config.
+// See: `Buffer()`.
+func BufferWithConfig(config BufferConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultBufferConfig.Skipper
+	}
+	if config.Limit == 0 {
+		config.Limit = DefaultBufferConfig.Limit
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			res := ctx.Response()
+			rw := res.Writer
+			w := &bufferedResponseWriter{
+				ResponseWriter: rw,
+				header:         cloneHeader(rw.Header()),
+				limit:          config.Limit,
+			}
+			res.Writer = w
+			defer func() {
+				res.Writer = rw
+			}()
+
+			err := next(ctx)
+			if w.passthrough {
+				return err
+			}
+			if err != nil {
+				// Discard, the error handler starts over
+				res.Committed = false
+				res.Status = http.StatusOK
+				res.Size = 0
+				return err
+			}
+			if res.Committed {
+				w.passThrough()
+			}
+			return nil
+		}
+	}
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	if w.passthrough {
+		return w.ResponseWriter.Header()
+	}
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.code = code
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	if !w.passthrough && w.buf.Len()+len(b) > w.limit {
+		w.passThrough()
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return w.buf.Write(b)
+}
+
+// passThrough sends what has been buffered and writes through from then on.
+func (w *bufferedResponseWriter) passThrough() {
+	if w.passthrough {
+		return
+	}
+	w.passthrough = true
+	header := w.ResponseWriter.Header()
+	for k := range header {
+		delete(header, k)
+	}
+	for k, v := range w.header {
+		header[k] = v
+	}
+	if w.code != 0 {
+		w.ResponseWriter.WriteHeader(w.code)
+	}
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+func (w *bufferedResponseWriter) Flush() {
+	w.passThrough()
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *bufferedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.passthrough = true
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+func (w *bufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func cloneHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
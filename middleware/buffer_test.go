@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffer(t *testing.T) {
+	a := akita.New()
+	a.Use(Buffer())
+	a.GET("/ok", func(ctx akita.Context) error {
+		ctx.Response().Header().Set("X-Handler", "ok")
+		return ctx.String(http.StatusCreated, "created")
+	})
+	a.GET("/partial", func(ctx akita.Context) error {
+		ctx.Response().Header().Set("X-Handler", "partial")
+		ctx.Response().Header().Set(akita.HeaderContentType, akita.MIMEApplicationJSONCharsetUTF8)
+		ctx.Response().WriteHeader(http.StatusOK)
+		ctx.Response().Write([]byte(`{"items":[1,2,`))
+		return errors.New("database failure")
+	})
+
+	req := httptest.NewRequest(akita.GET, "/ok", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "created", rec.Body.String())
+	assert.Equal(t, "ok", rec.Header().Get("X-Handler"))
+
+	req = httptest.NewRequest(akita.GET, "/partial", nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, `{"message":"Internal Server Error"}`, rec.Body.String())
+	assert.Empty(t, rec.Header().Get("X-Handler"))
+}
+
+func TestBufferLimit(t *testing.T) {
+	a := akita.New()
+	a.Use(BufferWithConfig(BufferConfig{Limit: 10}))
+	body := strings.Repeat("a", 20)
+	a.GET("/", func(ctx akita.Context) error {
+		ctx.Response().Write([]byte(body))
+		return errors.New("too late")
+	})
+
+	// Exceeding the limit passes the response through
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, body, rec.Body.String())
+}
@@ -0,0 +1,273 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// CacheConfig defines the config for Cache middleware.
+	CacheConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// TTL is the time a response is served from the cache.
+		// Required.
+		TTL time.Duration
+
+		// Store keeps the cached responses.
+		// Optional. Default value an in-memory LRU store of 1000 responses.
+		Store CacheStore
+
+		// KeyFunc returns the key a response is cached under.
+		// Optional. Default value the method, path and query of the request.
+		KeyFunc func(akita.Context) string
+
+		// Methods lists the HTTP methods whose responses are cached.
+		// Optional. Default value []string{GET, HEAD}.
+		Methods []string `json:"methods"`
+
+		// StatusCodes lists the response status codes which are cached.
+		// Optional. Default value []int{200}.
+		StatusCodes []int `json:"status_codes"`
+	}
+
+	// CacheStore stores the responses cached by the Cache middleware. It must
+	// be safe for concurrent use.
+	CacheStore interface {
+		// Get returns the response cached under key, if any and not expired.
+		Get(key string) (*CachedResponse, bool)
+
+		// Set caches res under key for ttl.
+		Set(key string, res *CachedResponse, ttl time.Duration)
+	}
+
+	// CachedResponse is a response stored by the Cache middleware.
+	CachedResponse struct {
+		Status int
+		Header http.Header
+		Body   []byte
+	}
+
+	memoryCacheStore struct {
+		mutex    sync.Mutex
+		capacity int
+		entries  map[string]*list.Element
+		lru      *list.List
+	}
+
+	memoryCacheEntry struct {
+		key     string
+		res     *CachedResponse
+		expires time.Time
+	}
+
+	cacheResponseWriter struct {
+		io.Writer
+		http.ResponseWriter
+	}
+)
+
+var (
+	// DefaultCacheConfig is the default Cache middleware config.
+	DefaultCacheConfig = CacheConfig{
+		Skipper: DefaultSkipper,
+		KeyFunc: func(ctx akita.Context) string {
+			req := ctx.Request()
+			return req.Method + " " + req.URL.Path + "?" + req.URL.RawQuery
+		},
+		Methods:     []string{akita.GET, akita.HEAD},
+		StatusCodes: []int{http.StatusOK},
+	}
+)
+
+// Cache returns a middleware which caches the responses for ttl in memory and
+// serves the cached copies until they expire. Requests with a
+// `Cache-Control: no-cache` header bypass the cache, their response replacing
+// the cached one. Only the headers set by the inner handlers are cached, not
+// the ones of the middleware added before, e.g. the request ID.
+func Cache(ttl time.Duration) akita.MiddlewareFunc {
+	c := DefaultCacheConfig
+	c.TTL = ttl
+	return CacheWithConfig(c)
+}
+
+// CacheWithConfig returns a Cache middleware with config.
+// See: `Cache()`.
+func CacheWithConfig(config CacheConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.TTL <= 0 {
+		panic("akita: cache middleware requires a ttl")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultCacheConfig.Skipper
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryCacheStore(1000)
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = DefaultCacheConfig.KeyFunc
+	}
+	if len(config.Methods) == 0 {
+		config.Methods = DefaultCacheConfig.Methods
+	}
+	if len(config.StatusCodes) == 0 {
+		config.StatusCodes = DefaultCacheConfig.StatusCodes
+	}
+
+	methods := map[string]bool{}
+	for _, m := range config.Methods {
+		methods[strings.ToUpper(m)] = true
+	}
+	codes := map[int]bool{}
+	for _, c := range config.StatusCodes {
+		codes[c] = true
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			req := ctx.Request()
+			if config.Skipper(ctx) || !methods[req.Method] {
+				return next(ctx)
+			}
+
+			key := config.KeyFunc(ctx)
+			noCache := strings.Contains(strings.ToLower(req.Header.Get(akita.HeaderCacheControl)), "no-cache")
+			if !noCache {
+				if cached, ok := config.Store.Get(key); ok {
+					res := ctx.Response()
+					for k, v := range cached.Header {
+						res.Header()[k] = append([]string(nil), v...)
+					}
+					res.WriteHeader(cached.Status)
+					_, err := res.Write(cached.Body)
+					return err
+				}
+			}
+
+			// Response
+			res := ctx.Response()
+			before := cloneHeader(res.Header())
+			body := new(bytes.Buffer)
+			rw := res.Writer
+			res.Writer = &cacheResponseWriter{Writer: io.MultiWriter(rw, body), ResponseWriter: rw}
+			defer func() {
+				res.Writer = rw
+			}()
+
+			if err := next(ctx); err != nil {
+				return err
+			}
+			if codes[res.Status] && res.Committed {
+				header := headerChanges(before, res.Header())
+				header.Del(akita.HeaderSetCookie)
+				config.Store.Set(key, &CachedResponse{
+					Status: res.Status,
+					Header: header,
+					Body:   body.Bytes(),
+				}, config.TTL)
+			}
+			return nil
+		}
+	}
+}
+
+// headerChanges returns the headers of after which were added or changed since
+// before, i.e. the ones set by the inner handlers and not by the middleware
+// around them, like the request ID, which must not be replayed to other
+// requests.
+func headerChanges(before, after http.Header) http.Header {
+	h := http.Header{}
+	for k, v := range after {
+		if old, ok := before[k]; ok && equalValues(old, v) {
+			continue
+		}
+		h[k] = append([]string(nil), v...)
+	}
+	return h
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NewMemoryCacheStore returns a CacheStore keeping up to capacity responses in
+// memory, evicting the least recently used ones first.
+func NewMemoryCacheStore(capacity int) CacheStore {
+	return &memoryCacheStore{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+	}
+}
+
+func (s *memoryCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*memoryCacheEntry)
+	if time.Now().After(entry.expires) {
+		s.lru.Remove(e)
+		delete(s.entries, key)
+		return nil, false
+	}
+	s.lru.MoveToFront(e)
+	return entry.res, true
+}
+
+func (s *memoryCacheStore) Set(key string, res *CachedResponse, ttl time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entry := &memoryCacheEntry{key: key, res: res, expires: time.Now().Add(ttl)}
+	if e, ok := s.entries[key]; ok {
+		e.Value = entry
+		s.lru.MoveToFront(e)
+		return
+	}
+	s.entries[key] = s.lru.PushFront(entry)
+	for s.capacity > 0 && s.lru.Len() > s.capacity {
+		e := s.lru.Back()
+		s.lru.Remove(e)
+		delete(s.entries, e.Value.(*memoryCacheEntry).key)
+	}
+}
+
+func (w *cacheResponseWriter) WriteHeader(code int) {
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheResponseWriter) Write(b []byte) (int, error) {
+	return w.Writer.Write(b)
+}
+
+func (w *cacheResponseWriter) Flush() {
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *cacheResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+func (w *cacheResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
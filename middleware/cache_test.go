@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	a := akita.New()
+	a.Use(Cache(100 * time.Millisecond))
+	calls := 0
+	a.GET("/", func(ctx akita.Context) error {
+		calls++
+		ctx.Response().Header().Set("X-Call", fmt.Sprint(calls))
+		return ctx.String(http.StatusOK, fmt.Sprintf("call %d", calls))
+	})
+	a.GET("/missing", func(ctx akita.Context) error {
+		calls++
+		return akita.ErrNotFound
+	})
+
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(akita.GET, path, nil)
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/")
+	assert.Equal(t, "call 1", rec.Body.String())
+
+	// Hit within the TTL
+	rec = get("/")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "call 1", rec.Body.String())
+	assert.Equal(t, "1", rec.Header().Get("X-Call"))
+	assert.Equal(t, akita.MIMETextPlainCharsetUTF8, rec.Header().Get(akita.HeaderContentType))
+
+	// Keyed by query
+	rec = get("/?page=2")
+	assert.Equal(t, "call 2", rec.Body.String())
+
+	// Bypassed by the client
+	rec = get("/", akita.HeaderCacheControl, "no-cache")
+	assert.Equal(t, "call 3", rec.Body.String())
+	rec = get("/")
+	assert.Equal(t, "call 3", rec.Body.String())
+
+	// Miss after expiry
+	time.Sleep(150 * time.Millisecond)
+	rec = get("/")
+	assert.Equal(t, "call 4", rec.Body.String())
+
+	// Errors aren't cached
+	get("/missing")
+	rec = get("/missing")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, 6, calls)
+
+	// Only GET and HEAD
+	req := httptest.NewRequest(akita.POST, "/", nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestCacheOuterHeaders(t *testing.T) {
+	a := akita.New()
+	a.Use(RequestID(), Cache(time.Minute))
+	a.GET("/", func(ctx akita.Context) error {
+		ctx.Response().Header().Set("X-Handler", "yes")
+		return ctx.String(http.StatusOK, "OK")
+	})
+
+	ids := []string{}
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(akita.GET, "/", nil)
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		assert.Equal(t, "OK", rec.Body.String())
+		assert.Equal(t, "yes", rec.Header().Get("X-Handler"))
+		ids = append(ids, rec.Header().Get(akita.HeaderXRequestID))
+	}
+	assert.NotEmpty(t, ids[1])
+	assert.NotEqual(t, ids[0], ids[1])
+}
+
+func TestMemoryCacheStore(t *testing.T) {
+	s := NewMemoryCacheStore(2)
+	s.Set("a", &CachedResponse{Status: 200}, time.Minute)
+	s.Set("b", &CachedResponse{Status: 201}, time.Minute)
+	_, ok := s.Get("a")
+	assert.True(t, ok)
+
+	// "b" is the least recently used
+	s.Set("c", &CachedResponse{Status: 202}, time.Minute)
+	_, ok = s.Get("b")
+	assert.False(t, ok)
+	res, ok := s.Get("a")
+	if assert.True(t, ok) {
+		assert.Equal(t, 200, res.Status)
+	}
+
+	s.Set("d", &CachedResponse{}, -time.Second)
+	_, ok = s.Get("d")
+	assert.False(t, ok)
+}
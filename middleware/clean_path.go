@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"path"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// CleanPathConfig defines the config for CleanPath middleware.
+	CleanPathConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Status code to be used when redirecting the request.
+		// Optional, but when provided the request is redirected using this code.
+		RedirectCode int `json:"redirect_code"`
+	}
+)
+
+var (
+	// DefaultCleanPathConfig is the default CleanPath middleware config.
+	DefaultCleanPathConfig = CleanPathConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// CleanPath returns a root level (before router) middleware which collapses
+// duplicate slashes and resolves dot segments in the request `URL#Path`, so
+// `/api//users/./1` is routed as `/api/users/1`. A trailing slash is kept as
+// it is, adding or removing it is left to the TrailingSlash middleware.
+//
+// Usage `Akita#Pre(CleanPath())`
+func CleanPath() akita.MiddlewareFunc {
+	return CleanPathWithConfig(DefaultCleanPathConfig)
+}
+
+// CleanPathWithConfig returns a CleanPath middleware with config.
+// See `CleanPath()`.
+func CleanPathWithConfig(config CleanPathConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultCleanPathConfig.Skipper
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			req := ctx.Request()
+			url := req.URL
+			p := cleanPath(url.Path)
+			if p != url.Path {
+				uri := p
+				if qs := ctx.QueryString(); qs != "" {
+					uri += "?" + qs
+				}
+
+				// Redirect
+				if config.RedirectCode != 0 {
+					return ctx.Redirect(config.RedirectCode, uri)
+				}
+
+				// Forward
+				req.RequestURI = uri
+				url.Path = p
+				url.RawPath = ""
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	c := path.Clean("/" + p)
+	if p[len(p)-1] == '/' && c != "/" {
+		c += "/"
+	}
+	return c
+}
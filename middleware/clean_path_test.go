@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanPath(t *testing.T) {
+	a := akita.New()
+	h := CleanPath()(func(ctx akita.Context) error {
+		return nil
+	})
+
+	for in, out := range map[string]string{
+		"/api//users///1":   "/api/users/1",
+		"/api/./users/../1": "/api/1",
+		"//api/users//":     "/api/users/",
+		"/api/users":        "/api/users",
+		"/":                 "/",
+	} {
+		req := httptest.NewRequest(akita.GET, in, nil)
+		rec := httptest.NewRecorder()
+		ctx := a.NewContext(req, rec)
+		h(ctx)
+		assert.Equal(t, out, req.URL.Path, in)
+		assert.Equal(t, out, req.RequestURI, in)
+	}
+
+	// With config
+	req := httptest.NewRequest(akita.GET, "/api//users?q=a//b", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	h = CleanPathWithConfig(CleanPathConfig{
+		RedirectCode: http.StatusMovedPermanently,
+	})(func(ctx akita.Context) error {
+		return nil
+	})
+	h(ctx)
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/api/users?q=a//b", rec.Header().Get(akita.HeaderLocation))
+}
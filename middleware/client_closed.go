@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	stdContext "context"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// ClientClosedConfig defines the config for ClientClosed middleware.
+	ClientClosedConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// StatusCode is the status of the response to the canceled requests.
+		// Optional. Default value 499.
+		StatusCode int `json:"status_code"`
+	}
+)
+
+// StatusClientClosedRequest is the non-standard status, introduced by nginx,
+// for the requests the client closed the connection of before the response.
+const StatusClientClosedRequest = 499
+
+var (
+	// DefaultClientClosedConfig is the default ClientClosed middleware config.
+	DefaultClientClosedConfig = ClientClosedConfig{
+		Skipper:    DefaultSkipper,
+		StatusCode: StatusClientClosedRequest,
+	}
+)
+
+// ClientClosed returns a middleware which answers with
+// "499 - Client Closed Request" instead of passing the error on to the
+// HTTPErrorHandler, which would log it and respond with 500, when the handler
+// returns `context.Canceled` or `context.DeadlineExceeded`, as is or as the
+// inner error of an `*akita.HTTPError`, and the request's context is done. It
+// is logged at the debug level only.
+func ClientClosed() akita.MiddlewareFunc {
+	return ClientClosedWithConfig(DefaultClientClosedConfig)
+}
+
+// ClientClosedWithConfig returns a ClientClosed middleware with config.
+// See: `ClientClosed()`.
+func ClientClosedWithConfig(config ClientClosedConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultClientClosedConfig.Skipper
+	}
+	if config.StatusCode == 0 {
+		config.StatusCode = DefaultClientClosedConfig.StatusCode
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			err := next(ctx)
+			if err == nil || ctx.Request().Context().Err() == nil || !isContextError(err) {
+				return err
+			}
+			ctx.Logger().Debugf("client closed request %s %s: %v", ctx.Request().Method, ctx.Request().RequestURI, err)
+			if ctx.Response().Committed {
+				return nil
+			}
+			return ctx.NoContent(config.StatusCode)
+		}
+	}
+}
+
+func isContextError(err error) bool {
+	if he, ok := err.(*akita.HTTPError); ok {
+		err = he.Inner
+	}
+	return err == stdContext.Canceled || err == stdContext.DeadlineExceeded
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	stdContext "context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientClosed(t *testing.T) {
+	a := akita.New()
+	buf := new(bytes.Buffer)
+	a.Logger.SetOutput(buf)
+	a.Use(ClientClosed())
+	a.GET("/", func(ctx akita.Context) error {
+		<-ctx.Request().Context().Done()
+		return ctx.Request().Context().Err()
+	})
+	a.GET("/fail", func(ctx akita.Context) error {
+		return errors.New("failure")
+	})
+
+	c, cancel := stdContext.WithCancel(stdContext.Background())
+	cancel()
+	req := httptest.NewRequest(akita.GET, "/", nil).WithContext(c)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, StatusClientClosedRequest, rec.Code)
+	assert.NotContains(t, buf.String(), "ERROR")
+
+	// Other errors are passed on
+	req = httptest.NewRequest(akita.GET, "/fail", nil).WithContext(c)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, buf.String(), "failure")
+}
+
+func TestClientClosedDeadline(t *testing.T) {
+	a := akita.New()
+	a.Logger.SetOutput(new(bytes.Buffer))
+	a.Use(ClientClosedWithConfig(ClientClosedConfig{StatusCode: http.StatusRequestTimeout}))
+	deadline := func(ctx akita.Context) error {
+		<-ctx.Request().Context().Done()
+		he := akita.NewHTTPError(http.StatusInternalServerError)
+		he.Inner = ctx.Request().Context().Err()
+		return he
+	}
+	a.GET("/", deadline)
+
+	c, cancel := stdContext.WithTimeout(stdContext.Background(), 0)
+	defer cancel()
+	req := httptest.NewRequest(akita.GET, "/", nil).WithContext(c)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestTimeout, rec.Code)
+
+	// Not canceled
+	a.GET("/canceled", func(ctx akita.Context) error {
+		return stdContext.Canceled
+	})
+	req = httptest.NewRequest(akita.GET, "/canceled", nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
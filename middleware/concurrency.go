@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// ConcurrencyLimitConfig defines the config for ConcurrencyLimit middleware.
+	ConcurrencyLimitConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Limit is the maximum number of requests handled concurrently.
+		// Required.
+		Limit int `json:"limit"`
+
+		// WaitTimeout is how long a request waits for another one to complete
+		// when the limit is reached.
+		// Optional. Default value 0, rejecting the request immediately.
+		WaitTimeout time.Duration `json:"wait_timeout"`
+
+		// LimitExceededHandler is called when the request is rejected.
+		// Optional. Default value returns `akita.ErrServiceUnavailable`.
+		LimitExceededHandler akita.HandlerFunc
+	}
+)
+
+var (
+	// DefaultConcurrencyLimitConfig is the default ConcurrencyLimit middleware config.
+	DefaultConcurrencyLimitConfig = ConcurrencyLimitConfig{
+		Skipper: DefaultSkipper,
+		LimitExceededHandler: func(ctx akita.Context) error {
+			return akita.ErrServiceUnavailable
+		},
+	}
+)
+
+// ConcurrencyLimit returns a middleware which caps the number of requests
+// handled concurrently, shedding the load above the limit with
+// "503 - Service Unavailable".
+func ConcurrencyLimit(limit int) akita.MiddlewareFunc {
+	c := DefaultConcurrencyLimitConfig
+	c.Limit = limit
+	return ConcurrencyLimitWithConfig(c)
+}
+
+// ConcurrencyLimitWithConfig returns a ConcurrencyLimit middleware with config.
+// See: `ConcurrencyLimit()`.
+func ConcurrencyLimitWithConfig(config ConcurrencyLimitConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Limit <= 0 {
+		panic("akita: concurrency limit middleware requires a positive limit")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultConcurrencyLimitConfig.Skipper
+	}
+	if config.LimitExceededHandler == nil {
+		config.LimitExceededHandler = DefaultConcurrencyLimitConfig.LimitExceededHandler
+	}
+
+	sem := make(chan struct{}, config.Limit)
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			select {
+			case sem <- struct{}{}:
+			default:
+				if config.WaitTimeout <= 0 {
+					return config.LimitExceededHandler(ctx)
+				}
+				t := time.NewTimer(config.WaitTimeout)
+				select {
+				case sem <- struct{}{}:
+					t.Stop()
+				case <-t.C:
+					return config.LimitExceededHandler(ctx)
+				case <-ctx.Request().Context().Done():
+					t.Stop()
+					return ctx.Request().Context().Err()
+				}
+			}
+			// Released on every exit path, panics included
+			defer func() {
+				<-sem
+			}()
+			return next(ctx)
+		}
+	}
+}
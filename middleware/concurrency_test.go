@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	a := akita.New()
+	release := make(chan struct{})
+	a.Use(ConcurrencyLimit(2))
+	a.GET("/", func(ctx akita.Context) error {
+		<-release
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	const n = 5
+	codes := make(chan int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(akita.GET, "/", nil))
+			codes <- rec.Code
+		}()
+	}
+	// Rejected requests complete while the others are blocked
+	for i := 0; i < n-2; i++ {
+		assert.Equal(t, http.StatusServiceUnavailable, <-codes)
+	}
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+}
+
+func TestConcurrencyLimitWait(t *testing.T) {
+	a := akita.New()
+	h := ConcurrencyLimitWithConfig(ConcurrencyLimitConfig{
+		Limit:       1,
+		WaitTimeout: time.Second,
+	})(func(ctx akita.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := a.NewContext(httptest.NewRequest(akita.GET, "/", nil), httptest.NewRecorder())
+			assert.NoError(t, h(ctx))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrencyLimitPanic(t *testing.T) {
+	a := akita.New()
+	h := ConcurrencyLimit(1)(func(ctx akita.Context) error {
+		panic("test")
+	})
+	for i := 0; i < 2; i++ {
+		ctx := a.NewContext(httptest.NewRequest(akita.GET, "/", nil), httptest.NewRecorder())
+		assert.Panics(t, func() {
+			h(ctx)
+		})
+	}
+
+	assert.Panics(t, func() {
+		ConcurrencyLimit(0)
+	})
+}
@@ -0,0 +1,92 @@
+//go:build go1.20
+// +build go1.20
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// ConnDeadlineConfig defines the config for ConnDeadline middleware.
+	ConnDeadlineConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// ReadTimeout is the time the request body has to be read in, from
+		// the moment the handler chain starts.
+		// Optional. Default value 0, meaning no deadline.
+		ReadTimeout time.Duration `json:"read_timeout"`
+
+		// WriteTimeout is the time the response has to be written in, from
+		// the moment the handler chain starts.
+		// Optional. Default value 0, meaning no deadline.
+		WriteTimeout time.Duration `json:"write_timeout"`
+	}
+)
+
+var (
+	// DefaultConnDeadlineConfig is the default ConnDeadline middleware config.
+	DefaultConnDeadlineConfig = ConnDeadlineConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// ConnDeadline returns a middleware which sets read and write deadlines on the
+// connection of the request, using `http.ResponseController`, so that slow
+// clients can't hold a handler reading the body or streaming the response
+// longer than allowed. Reads and writes past the deadlines fail with a timeout
+// error. Unlike the `http.Server` timeouts, they can be set per route or group.
+// The server resets the deadlines after the request.
+//
+// The deadlines are set through the response writer, which the wrappers of the
+// middleware of this package unwrap. It must be added before the middleware
+// wrapping the writer without an `Unwrap() http.ResponseWriter` method,
+// otherwise it does nothing but log a warning once.
+func ConnDeadline(read, write time.Duration) akita.MiddlewareFunc {
+	c := DefaultConnDeadlineConfig
+	c.ReadTimeout = read
+	c.WriteTimeout = write
+	return ConnDeadlineWithConfig(c)
+}
+
+// ConnDeadlineWithConfig returns a ConnDeadline middleware with config.
+// See: `ConnDeadline()`.
+func ConnDeadlineWithConfig(config ConnDeadlineConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultConnDeadlineConfig.Skipper
+	}
+
+	var warnOnce sync.Once
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			rc := http.NewResponseController(ctx.Response().Writer)
+			now := time.Now()
+			var err error
+			if config.ReadTimeout > 0 {
+				err = rc.SetReadDeadline(now.Add(config.ReadTimeout))
+			}
+			if err == nil && config.WriteTimeout > 0 {
+				err = rc.SetWriteDeadline(now.Add(config.WriteTimeout))
+			}
+			if errors.Is(err, http.ErrNotSupported) {
+				warnOnce.Do(func() {
+					ctx.Logger().Warnf("conn deadline: response writer %T doesn't support deadlines", ctx.Response().Writer)
+				})
+			} else if err != nil {
+				return err
+			}
+			return next(ctx)
+		}
+	}
+}
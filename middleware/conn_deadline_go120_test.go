@@ -0,0 +1,93 @@
+//go:build go1.20
+// +build go1.20
+
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itchenyi/akita"
+	"github.com/itchenyi/common/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnDeadline(t *testing.T) {
+	a := akita.New()
+	a.Use(ConnDeadline(100*time.Millisecond, time.Second))
+	testConnDeadline(t, a)
+}
+
+func TestConnDeadlineGzip(t *testing.T) {
+	a := akita.New()
+	a.Use(Gzip(), ConnDeadline(100*time.Millisecond, time.Second))
+	testConnDeadline(t, a)
+}
+
+func TestConnDeadlineNotSupported(t *testing.T) {
+	a := akita.New()
+	buf := new(bytes.Buffer)
+	a.Logger.SetOutput(buf)
+	a.Logger.SetLevel(log.WARN)
+	h := ConnDeadline(time.Second, time.Second)(func(ctx akita.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		ctx := a.NewContext(httptest.NewRequest(akita.GET, "/", nil), rec)
+		assert.NoError(t, h(ctx))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, 1, strings.Count(buf.String(), "doesn't support deadlines"))
+}
+
+func testConnDeadline(t *testing.T, a *akita.Akita) {
+	errCh := make(chan error, 1)
+	a.POST("/", func(ctx akita.Context) error {
+		_, err := ioutil.ReadAll(ctx.Request().Body)
+		errCh <- err
+		if err != nil {
+			return akita.NewHTTPError(http.StatusRequestTimeout)
+		}
+		return ctx.NoContent(http.StatusOK)
+	})
+	s := httptest.NewServer(a)
+	defer s.Close()
+
+	// Slow body
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte("a"))
+		time.Sleep(300 * time.Millisecond)
+		pw.Write([]byte("b"))
+		pw.Close()
+	}()
+	res, err := http.Post(s.URL, akita.MIMETextPlain, pr)
+	if err == nil {
+		res.Body.Close()
+	}
+	select {
+	case err := <-errCh:
+		if assert.Error(t, err) {
+			ne, ok := err.(net.Error)
+			assert.True(t, ok && ne.Timeout())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler not done")
+	}
+
+	// Fast body
+	res, err = http.Post(s.URL, akita.MIMETextPlain, nil)
+	if assert.NoError(t, err) {
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.NoError(t, <-errCh)
+	}
+}
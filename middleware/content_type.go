@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// ContentTypeConfig defines the config for EnforceContentType middleware.
+	ContentTypeConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Types is the list of media types allowed for request bodies, e.g.
+		// "application/json". Wildcards like "application/*" and "*/*" match any
+		// subtype and type respectively.
+		// Required.
+		Types []string `json:"types"`
+	}
+)
+
+var (
+	// DefaultContentTypeConfig is the default EnforceContentType middleware config.
+	DefaultContentTypeConfig = ContentTypeConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// EnforceContentType returns a middleware which rejects POST, PUT and PATCH
+// requests with a body whose `Content-Type` is not one of the allowed types,
+// sending "415 - Unsupported Media Type" response.
+func EnforceContentType(types ...string) akita.MiddlewareFunc {
+	c := DefaultContentTypeConfig
+	c.Types = types
+	return EnforceContentTypeWithConfig(c)
+}
+
+// EnforceContentTypeWithConfig returns an EnforceContentType middleware with config.
+// See: `EnforceContentType()`.
+func EnforceContentTypeWithConfig(config ContentTypeConfig) akita.MiddlewareFunc {
+	// Defaults
+	if len(config.Types) == 0 {
+		panic("akita: content type middleware requires allowed types")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultContentTypeConfig.Skipper
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			req := ctx.Request()
+			switch req.Method {
+			case akita.POST, akita.PUT, akita.PATCH:
+			default:
+				return next(ctx)
+			}
+			if req.ContentLength == 0 {
+				return next(ctx)
+			}
+
+			mediaType, _, err := mime.ParseMediaType(req.Header.Get(akita.HeaderContentType))
+			if err != nil || !matchContentType(mediaType, config.Types) {
+				return akita.ErrUnsupportedMediaType
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func matchContentType(mediaType string, types []string) bool {
+	for _, t := range types {
+		t = strings.ToLower(t)
+		if t == "*/*" || t == mediaType {
+			return true
+		}
+		if strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, t[:len(t)-1]) {
+			return true
+		}
+	}
+	return false
+}
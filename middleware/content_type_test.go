@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnforceContentType(t *testing.T) {
+	a := akita.New()
+	h := EnforceContentType(akita.MIMEApplicationJSON, "text/*")(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})
+
+	tests := []struct {
+		method, contentType string
+		body                string
+		err                 error
+	}{
+		{akita.POST, akita.MIMEApplicationJSONCharsetUTF8, "{}", nil},
+		{akita.PUT, akita.MIMETextPlain, "test", nil},
+		{akita.PATCH, akita.MIMEApplicationXML, "<a/>", akita.ErrUnsupportedMediaType},
+		{akita.POST, "", "test", akita.ErrUnsupportedMediaType},
+		{akita.POST, akita.MIMEApplicationXML, "", nil},
+		{akita.GET, akita.MIMEApplicationXML, "<a/>", nil},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set(akita.HeaderContentType, tt.contentType)
+		}
+		rec := httptest.NewRecorder()
+		ctx := a.NewContext(req, rec)
+		err := h(ctx)
+		assert.Equal(t, tt.err, err, tt.method+" "+tt.contentType)
+		if err == nil {
+			assert.Equal(t, "test", rec.Body.String())
+		}
+	}
+
+	assert.Panics(t, func() {
+		EnforceContentType()
+	})
+}
@@ -44,9 +44,19 @@ type (
 		// can be cached.
 		// Optional. Default value 0.
 		MaxAge int `json:"max_age"`
+
+		// PreflightContinue passes the preflight request on to the next handler
+		// after setting the CORS headers, instead of responding with 204. Use it
+		// with routes registering OPTIONS explicitly. If the handler doesn't write
+		// a response, or the route has no OPTIONS handler, 204 is still sent.
+		// Optional. Default value false.
+		PreflightContinue bool `json:"preflight_continue"`
 	}
 )
 
+// CORSRouteDataKey is the key of the per-route `CORSConfig` in the route data.
+const CORSRouteDataKey = "cors"
+
 var (
 	// DefaultCORSConfig is the default CORS middleware config.
 	DefaultCORSConfig = CORSConfig{
@@ -56,8 +66,29 @@ var (
 	}
 )
 
+// corsPolicy is a CORS config with its headers prepared.
+type corsPolicy struct {
+	config        CORSConfig
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	maxAge        string
+}
+
 // CORS returns a Cross-Origin Resource Sharing (CORS) middleware.
 // See: https://developer.mozilla.org/en/docs/Web/HTTP/Access_control_CORS
+//
+// Routes may have their own policy, e.g. a credentialed one, with a
+// `CORSConfig` stored in their data under `CORSRouteDataKey`:
+//
+//	a.GET("/account", h).SetData(middleware.CORSRouteDataKey, middleware.CORSConfig{
+//		AllowOrigins:     []string{"https://app.example.com"},
+//		AllowCredentials: true,
+//	})
+//
+// Preflight requests get the policy of the route for the method in their
+// `Access-Control-Request-Method` header. The middleware must be added with
+// `Use()` for the routes to be known.
 func CORS() akita.MiddlewareFunc {
 	return CORSWithConfig(DefaultCORSConfig)
 }
@@ -69,17 +100,7 @@ func CORSWithConfig(config CORSConfig) akita.MiddlewareFunc {
 	if config.Skipper == nil {
 		config.Skipper = DefaultCORSConfig.Skipper
 	}
-	if len(config.AllowOrigins) == 0 {
-		config.AllowOrigins = DefaultCORSConfig.AllowOrigins
-	}
-	if len(config.AllowMethods) == 0 {
-		config.AllowMethods = DefaultCORSConfig.AllowMethods
-	}
-
-	allowMethods := strings.Join(config.AllowMethods, ",")
-	allowHeaders := strings.Join(config.AllowHeaders, ",")
-	exposeHeaders := strings.Join(config.ExposeHeaders, ",")
-	maxAge := strconv.Itoa(config.MaxAge)
+	policy := newCORSPolicy(config)
 
 	return func(next akita.HandlerFunc) akita.HandlerFunc {
 		return func(ctx akita.Context) error {
@@ -92,6 +113,21 @@ func CORSWithConfig(config CORSConfig) akita.MiddlewareFunc {
 			origin := req.Header.Get(akita.HeaderOrigin)
 			allowOrigin := ""
 
+			// Route policy
+			policy := policy
+			method := req.Method
+			if method == akita.OPTIONS {
+				if m := req.Header.Get(akita.HeaderAccessControlRequestMethod); m != "" {
+					method = m
+				}
+			}
+			if r := ctx.Akita().FindRoute(req.Host, method, ctx.Path()); r != nil {
+				if c, ok := r.Data[CORSRouteDataKey].(CORSConfig); ok {
+					policy = newCORSPolicy(c)
+				}
+			}
+			config := policy.config
+
 			// Check allowed origins
 			for _, o := range config.AllowOrigins {
 				if o == "*" || o == origin {
@@ -107,8 +143,8 @@ func CORSWithConfig(config CORSConfig) akita.MiddlewareFunc {
 				if config.AllowCredentials {
 					res.Header().Set(akita.HeaderAccessControlAllowCredentials, "true")
 				}
-				if exposeHeaders != "" {
-					res.Header().Set(akita.HeaderAccessControlExposeHeaders, exposeHeaders)
+				if policy.exposeHeaders != "" {
+					res.Header().Set(akita.HeaderAccessControlExposeHeaders, policy.exposeHeaders)
 				}
 				return next(ctx)
 			}
@@ -118,12 +154,12 @@ func CORSWithConfig(config CORSConfig) akita.MiddlewareFunc {
 			res.Header().Add(akita.HeaderVary, akita.HeaderAccessControlRequestMethod)
 			res.Header().Add(akita.HeaderVary, akita.HeaderAccessControlRequestHeaders)
 			res.Header().Set(akita.HeaderAccessControlAllowOrigin, allowOrigin)
-			res.Header().Set(akita.HeaderAccessControlAllowMethods, allowMethods)
+			res.Header().Set(akita.HeaderAccessControlAllowMethods, policy.allowMethods)
 			if config.AllowCredentials {
 				res.Header().Set(akita.HeaderAccessControlAllowCredentials, "true")
 			}
-			if allowHeaders != "" {
-				res.Header().Set(akita.HeaderAccessControlAllowHeaders, allowHeaders)
+			if policy.allowHeaders != "" {
+				res.Header().Set(akita.HeaderAccessControlAllowHeaders, policy.allowHeaders)
 			} else {
 				h := req.Header.Get(akita.HeaderAccessControlRequestHeaders)
 				if h != "" {
@@ -131,9 +167,36 @@ func CORSWithConfig(config CORSConfig) akita.MiddlewareFunc {
 				}
 			}
 			if config.MaxAge > 0 {
-				res.Header().Set(akita.HeaderAccessControlMaxAge, maxAge)
+				res.Header().Set(akita.HeaderAccessControlMaxAge, policy.maxAge)
+			}
+			if config.PreflightContinue {
+				err := next(ctx)
+				// No OPTIONS route
+				if he, ok := err.(*akita.HTTPError); ok && he.Code == http.StatusMethodNotAllowed && !res.Committed {
+					err = nil
+				}
+				if err != nil || res.Committed {
+					return err
+				}
 			}
 			return ctx.NoContent(http.StatusNoContent)
 		}
 	}
 }
+
+// newCORSPolicy applies the defaults to config and prepares its headers.
+func newCORSPolicy(config CORSConfig) *corsPolicy {
+	if len(config.AllowOrigins) == 0 {
+		config.AllowOrigins = DefaultCORSConfig.AllowOrigins
+	}
+	if len(config.AllowMethods) == 0 {
+		config.AllowMethods = DefaultCORSConfig.AllowMethods
+	}
+	return &corsPolicy{
+		config:        config,
+		allowMethods:  strings.Join(config.AllowMethods, ","),
+		allowHeaders:  strings.Join(config.AllowHeaders, ","),
+		exposeHeaders: strings.Join(config.ExposeHeaders, ","),
+		maxAge:        strconv.Itoa(config.MaxAge),
+	}
+}
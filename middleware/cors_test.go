@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -48,3 +49,96 @@ func TestCORS(t *testing.T) {
 	assert.Equal(t, "true", rec.Header().Get(akita.HeaderAccessControlAllowCredentials))
 	assert.Equal(t, "3600", rec.Header().Get(akita.HeaderAccessControlMaxAge))
 }
+
+func TestCORSPreflightContinue(t *testing.T) {
+	a := akita.New()
+	a.Use(CORSWithConfig(CORSConfig{
+		AllowOrigins:      []string{"localhost"},
+		PreflightContinue: true,
+	}))
+	a.OPTIONS("/custom", func(ctx akita.Context) error {
+		ctx.Response().Header().Set("X-Preflight", "custom")
+		return ctx.NoContent(http.StatusOK)
+	})
+	a.OPTIONS("/silent", func(ctx akita.Context) error {
+		return nil
+	})
+	a.GET("/users", func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "users")
+	})
+
+	req := httptest.NewRequest(akita.OPTIONS, "/custom", nil)
+	req.Header.Set(akita.HeaderOrigin, "localhost")
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "custom", rec.Header().Get("X-Preflight"))
+	assert.Equal(t, "localhost", rec.Header().Get(akita.HeaderAccessControlAllowOrigin))
+	assert.NotEmpty(t, rec.Header().Get(akita.HeaderAccessControlAllowMethods))
+
+	// Handler not writing a response
+	req = httptest.NewRequest(akita.OPTIONS, "/silent", nil)
+	req.Header.Set(akita.HeaderOrigin, "localhost")
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "localhost", rec.Header().Get(akita.HeaderAccessControlAllowOrigin))
+
+	// Route registered for GET only
+	req = httptest.NewRequest(akita.OPTIONS, "/users", nil)
+	req.Header.Set(akita.HeaderOrigin, "localhost")
+	req.Header.Set(akita.HeaderAccessControlRequestMethod, akita.GET)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "localhost", rec.Header().Get(akita.HeaderAccessControlAllowOrigin))
+}
+
+func TestCORSRouteConfig(t *testing.T) {
+	a := akita.New()
+	a.Use(CORS())
+	h := func(ctx akita.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	}
+	a.GET("/public", h)
+	a.POST("/account", h).SetData(CORSRouteDataKey, CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com"},
+		AllowMethods:     []string{akita.POST},
+		AllowCredentials: true,
+	})
+
+	// Public
+	req := httptest.NewRequest(akita.GET, "/public", nil)
+	req.Header.Set(akita.HeaderOrigin, "https://example.org")
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, "*", rec.Header().Get(akita.HeaderAccessControlAllowOrigin))
+	assert.Empty(t, rec.Header().Get(akita.HeaderAccessControlAllowCredentials))
+
+	// Credentialed
+	req = httptest.NewRequest(akita.POST, "/account", nil)
+	req.Header.Set(akita.HeaderOrigin, "https://app.example.com")
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get(akita.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "true", rec.Header().Get(akita.HeaderAccessControlAllowCredentials))
+
+	// Preflight
+	req = httptest.NewRequest(akita.OPTIONS, "/account", nil)
+	req.Header.Set(akita.HeaderOrigin, "https://example.org")
+	req.Header.Set(akita.HeaderAccessControlRequestMethod, akita.POST)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get(akita.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, akita.POST, rec.Header().Get(akita.HeaderAccessControlAllowMethods))
+	assert.Equal(t, "true", rec.Header().Get(akita.HeaderAccessControlAllowCredentials))
+
+	req = httptest.NewRequest(akita.OPTIONS, "/public", nil)
+	req.Header.Set(akita.HeaderOrigin, "https://example.org")
+	req.Header.Set(akita.HeaderAccessControlRequestMethod, akita.GET)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get(akita.HeaderAccessControlAllowOrigin))
+}
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"crypto/rand"
 	"crypto/subtle"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -57,6 +60,19 @@ type (
 		// Indicates if CSRF cookie is HTTP only.
 		// Optional. Default value false.
 		CookieHTTPOnly bool `json:"cookie_http_only"`
+
+		// SameSite attribute of the CSRF cookie, the `CookieSameSite` field,
+		// which requires Go 1.11. Being promoted from an embedded struct, it
+		// is set by assignment rather than in a composite literal.
+		// Optional. Default value http.SameSiteDefaultMode.
+		csrfSameSiteConfig
+
+		// MaskToken indicates if the token stored in the context is masked with
+		// a random pad on every request, which mitigates BREACH attacks when the
+		// token is embedded in compressed responses. The cookie keeps the
+		// unmasked secret and both forms are accepted on validation.
+		// Optional. Default value false.
+		MaskToken bool `json:"mask_token"`
 	}
 
 	// csrfTokenExtractor defines a function that takes `akita.Context` and returns
@@ -64,6 +80,10 @@ type (
 	csrfTokenExtractor func(akita.Context) (string, error)
 )
 
+// csrfTokenKey is the context key the token is also stored under, for
+// `CSRFTokenFrom()`.
+const csrfTokenKey = "akita.csrf"
+
 var (
 	// DefaultCSRFConfig is the default CSRF middleware config.
 	DefaultCSRFConfig = CSRFConfig{
@@ -160,10 +180,15 @@ func CSRFWithConfig(config CSRFConfig) akita.MiddlewareFunc {
 			cookie.Expires = time.Now().Add(time.Duration(config.CookieMaxAge) * time.Second)
 			cookie.Secure = config.CookieSecure
 			cookie.HttpOnly = config.CookieHTTPOnly
+			config.setCookieSameSite(cookie)
 			ctx.SetCookie(cookie)
 
 			// Store token in the context
+			if config.MaskToken {
+				token = maskCSRFToken(token)
+			}
 			ctx.Set(config.ContextKey, token)
+			ctx.Set(csrfTokenKey, token)
 
 			// Protect clients from caching the response
 			ctx.Response().Header().Add(akita.HeaderVary, akita.HeaderCookie)
@@ -173,6 +198,13 @@ func CSRFWithConfig(config CSRFConfig) akita.MiddlewareFunc {
 	}
 }
 
+// CSRFTokenFrom returns the CSRF token stored in the context by the CSRF
+// middleware, whatever its context key.
+func CSRFTokenFrom(ctx akita.Context) string {
+	token, _ := ctx.Get(csrfTokenKey).(string)
+	return token
+}
+
 // csrfTokenFromForm returns a `csrfTokenExtractor` that extracts token from the
 // provided request header.
 func csrfTokenFromHeader(header string) csrfTokenExtractor {
@@ -206,5 +238,37 @@ func csrfTokenFromQuery(param string) csrfTokenExtractor {
 }
 
 func validateCSRFToken(token, clientToken string) bool {
+	if t, ok := unmaskCSRFToken(clientToken, len(token)); ok {
+		clientToken = t
+	}
 	return subtle.ConstantTimeCompare([]byte(token), []byte(clientToken)) == 1
 }
+
+// maskCSRFToken XORs the token with a random pad of the same length and returns
+// the base64 encoded pad followed by the result.
+func maskCSRFToken(token string) string {
+	pad := make([]byte, len(token))
+	if _, err := rand.Read(pad); err != nil {
+		panic(fmt.Errorf("akita: csrf token masking failed, error=%v", err))
+	}
+	masked := make([]byte, 2*len(token))
+	copy(masked, pad)
+	for i := 0; i < len(token); i++ {
+		masked[len(token)+i] = pad[i] ^ token[i]
+	}
+	return base64.RawURLEncoding.EncodeToString(masked)
+}
+
+// unmaskCSRFToken reverses `maskCSRFToken()`. It returns false if the provided
+// token is not a masked token of the expected length.
+func unmaskCSRFToken(masked string, length int) (string, bool) {
+	b, err := base64.RawURLEncoding.DecodeString(masked)
+	if err != nil || length == 0 || len(b) != 2*length {
+		return "", false
+	}
+	token := make([]byte, length)
+	for i := 0; i < length; i++ {
+		token[i] = b[i] ^ b[length+i]
+	}
+	return string(token), true
+}
@@ -0,0 +1,12 @@
+//go:build !go1.11
+// +build !go1.11
+
+package middleware
+
+import "net/http"
+
+type (
+	csrfSameSiteConfig struct{}
+)
+
+func (csrfSameSiteConfig) setCookieSameSite(*http.Cookie) {}
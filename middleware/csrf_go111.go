@@ -0,0 +1,21 @@
+//go:build go1.11
+// +build go1.11
+
+package middleware
+
+import "net/http"
+
+type (
+	csrfSameSiteConfig struct {
+		// SameSite attribute of the CSRF cookie.
+		// Optional. Default value http.SameSiteDefaultMode.
+		CookieSameSite http.SameSite `json:"cookie_same_site"`
+	}
+)
+
+func (c csrfSameSiteConfig) setCookieSameSite(cookie *http.Cookie) {
+	cookie.SameSite = c.CookieSameSite
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteDefaultMode
+	}
+}
@@ -0,0 +1,29 @@
+//go:build go1.11
+// +build go1.11
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRFSameSite(t *testing.T) {
+	a := akita.New()
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	config := CSRFConfig{}
+	config.CookieSameSite = http.SameSiteStrictMode
+	csrf := CSRFWithConfig(config)
+	h := csrf(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})
+	if assert.NoError(t, h(ctx)) {
+		assert.Contains(t, rec.Header().Get(akita.HeaderSetCookie), "SameSite=Strict")
+	}
+}
@@ -80,3 +80,36 @@ func TestCSRFTokenFromQuery(t *testing.T) {
 	assert.Error(t, err)
 	csrfTokenFromQuery("csrf")
 }
+
+func TestCSRFMaskToken(t *testing.T) {
+	secret := random.String(32)
+	t1 := maskCSRFToken(secret)
+	t2 := maskCSRFToken(secret)
+	assert.NotEqual(t, t1, t2)
+	assert.NotEqual(t, secret, t1)
+	assert.True(t, validateCSRFToken(secret, t1))
+	assert.True(t, validateCSRFToken(secret, t2))
+	assert.True(t, validateCSRFToken(secret, secret))
+	assert.False(t, validateCSRFToken(secret, maskCSRFToken(random.String(32))))
+
+	// Middleware
+	a := akita.New()
+	csrf := CSRFWithConfig(CSRFConfig{
+		ContextKey: "token",
+		MaskToken:  true,
+	})
+	h := csrf(func(ctx akita.Context) error {
+		assert.Equal(t, ctx.Get("token"), CSRFTokenFrom(ctx))
+		return ctx.String(http.StatusOK, CSRFTokenFrom(ctx))
+	})
+	req := httptest.NewRequest(akita.POST, "/", nil)
+	req.Header.Set(akita.HeaderCookie, "_csrf="+secret)
+	req.Header.Set(akita.HeaderXCSRFToken, t1)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		token := rec.Body.String()
+		assert.NotEqual(t, secret, token)
+		assert.True(t, validateCSRFToken(secret, token))
+	}
+}
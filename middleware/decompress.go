@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// DecompressConfig defines the config for Decompress middleware.
+	DecompressConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+	}
+)
+
+var (
+	// DefaultDecompressConfig is the default Decompress middleware config.
+	DefaultDecompressConfig = DecompressConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// Decompress returns a middleware which decompresses the request body if it is
+// sent with `Content-Encoding: gzip`, so handlers and the binder see the plain
+// content.
+func Decompress() akita.MiddlewareFunc {
+	return DecompressWithConfig(DefaultDecompressConfig)
+}
+
+// DecompressWithConfig returns a Decompress middleware with config.
+// See: `Decompress()`.
+func DecompressWithConfig(config DecompressConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultDecompressConfig.Skipper
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			req := ctx.Request()
+			if !strings.EqualFold(strings.TrimSpace(req.Header.Get(akita.HeaderContentEncoding)), gzipScheme) {
+				return next(ctx)
+			}
+
+			body := req.Body
+			gr, err := gzip.NewReader(body)
+			if err != nil {
+				return akita.NewHTTPError(http.StatusBadRequest, "Malformed gzip request body")
+			}
+			defer func() {
+				gr.Close()
+				req.Body = body
+			}()
+
+			// The length of the decompressed content is unknown
+			req.Header.Del(akita.HeaderContentEncoding)
+			req.Header.Del(akita.HeaderContentLength)
+			req.ContentLength = -1
+			req.Body = gr
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecompress(t *testing.T) {
+	a := akita.New()
+	body := `{"name":"Jon Snow"}`
+	h := Decompress()(func(ctx akita.Context) error {
+		u := new(struct {
+			Name string `json:"name"`
+		})
+		if err := ctx.Bind(u); err != nil {
+			return err
+		}
+		return ctx.String(http.StatusOK, u.Name)
+	})
+
+	// Plain body
+	req := httptest.NewRequest(akita.POST, "/", strings.NewReader(body))
+	req.Header.Set(akita.HeaderContentType, akita.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, "Jon Snow", rec.Body.String())
+	}
+
+	// Gzip body
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	gw.Write([]byte(body))
+	gw.Close()
+	original := ioutil.NopCloser(buf)
+	req = httptest.NewRequest(akita.POST, "/", nil)
+	req.Body = original
+	req.ContentLength = int64(buf.Len())
+	req.Header.Set(akita.HeaderContentType, akita.MIMEApplicationJSON)
+	req.Header.Set(akita.HeaderContentEncoding, gzipScheme)
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, "Jon Snow", rec.Body.String())
+		assert.Equal(t, original, req.Body)
+	}
+
+	// Malformed gzip body
+	req = httptest.NewRequest(akita.POST, "/", strings.NewReader(body))
+	req.Header.Set(akita.HeaderContentType, akita.MIMEApplicationJSON)
+	req.Header.Set(akita.HeaderContentEncoding, gzipScheme)
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	err := h(ctx)
+	if assert.IsType(t, new(akita.HTTPError), err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*akita.HTTPError).Code)
+	}
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/itchenyi/akita"
+	cbytes "github.com/itchenyi/common/bytes"
+)
+
+type (
+	// ETagConfig defines the config for ETag middleware.
+	ETagConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Weak indicates if weak ETags (`W/"..."`) are generated instead of
+		// strong ones.
+		// Optional. Default value false.
+		Weak bool `json:"weak"`
+
+		// Maximum size of a response body which is buffered to compute the ETag,
+		// larger responses are streamed to the client without one. It can be
+		// specified as `4x` or `4xB`, where x is one of the multiple from K, M,
+		// G, T or P.
+		// Optional. Default value "1M".
+		Limit string `json:"limit"`
+		limit int64
+	}
+
+	etagResponseWriter struct {
+		http.ResponseWriter
+		buffer      *bytes.Buffer
+		code        int
+		limit       int64
+		passthrough bool
+	}
+)
+
+var (
+	// DefaultETagConfig is the default ETag middleware config.
+	DefaultETagConfig = ETagConfig{
+		Skipper: DefaultSkipper,
+		Limit:   "1M",
+	}
+)
+
+// ETag returns a middleware which generates an ETag for `GET` and `HEAD`
+// responses and sends "304 - Not Modified" when it matches the `If-None-Match`
+// request header.
+//
+// To hash the uncompressed body, register it after the Gzip middleware, e.g.
+// `Akita#Use(Gzip(), ETag())`.
+func ETag() akita.MiddlewareFunc {
+	return ETagWithConfig(DefaultETagConfig)
+}
+
+// ETagWithConfig returns an ETag middleware with config.
+// See: `ETag()`.
+func ETagWithConfig(config ETagConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultETagConfig.Skipper
+	}
+	if config.Limit == "" {
+		config.Limit = DefaultETagConfig.Limit
+	}
+
+	limit, err := cbytes.Parse(config.Limit)
+	if err != nil {
+		panic(fmt.Errorf("akita: invalid etag limit=%s", config.Limit))
+	}
+	config.limit = limit
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) (err error) {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			req := ctx.Request()
+			if req.Method != akita.GET && req.Method != akita.HEAD {
+				return next(ctx)
+			}
+
+			res := ctx.Response()
+			rw := res.Writer
+			w := &etagResponseWriter{ResponseWriter: rw, buffer: new(bytes.Buffer), limit: config.limit}
+			res.Writer = w
+			err = next(ctx)
+			res.Writer = rw
+
+			if w.passthrough {
+				return
+			}
+			if err != nil || w.code != http.StatusOK || w.buffer.Len() == 0 {
+				w.flush()
+				return
+			}
+
+			etag := fmt.Sprintf(`"%x"`, sha1.Sum(w.buffer.Bytes()))
+			if config.Weak {
+				etag = "W/" + etag
+			}
+			res.Header().Set(akita.HeaderETag, etag)
+			if etagMatch(req.Header.Get(akita.HeaderIfNoneMatch), etag) {
+				res.Header().Del(akita.HeaderContentLength)
+				res.Status = http.StatusNotModified
+				res.Size = 0
+				rw.WriteHeader(http.StatusNotModified)
+				return
+			}
+			return w.flush()
+		}
+	}
+}
+
+// etagMatch reports whether the `If-None-Match` header value matches the
+// provided ETag using the weak comparison function.
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// flush sends the buffered status code and body to the client and switches the
+// writer to pass-through mode.
+func (w *etagResponseWriter) flush() (err error) {
+	if w.passthrough {
+		return
+	}
+	w.passthrough = true
+	if w.code != 0 {
+		w.ResponseWriter.WriteHeader(w.code)
+	}
+	if w.buffer.Len() > 0 {
+		_, err = w.ResponseWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+	}
+	return
+}
+
+func (w *etagResponseWriter) WriteHeader(code int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.code = code
+}
+
+func (w *etagResponseWriter) Write(b []byte) (int, error) {
+	if !w.passthrough && int64(w.buffer.Len()+len(b)) > w.limit {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buffer.Write(b)
+}
+
+func (w *etagResponseWriter) Flush() {
+	w.flush()
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (w *etagResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+func (w *etagResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *etagResponseWriter) CloseNotify() <-chan bool {
+	return w.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	a := akita.New()
+	h := ETag()(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})
+
+	// Miss
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "test", rec.Body.String())
+	}
+	etag := rec.Header().Get(akita.HeaderETag)
+	assert.True(t, strings.HasPrefix(etag, `"`))
+
+	// Hit
+	req = httptest.NewRequest(akita.GET, "/", nil)
+	req.Header.Set(akita.HeaderIfNoneMatch, etag)
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, etag, rec.Header().Get(akita.HeaderETag))
+		assert.Empty(t, rec.Body.String())
+	}
+
+	// Stale
+	req = httptest.NewRequest(akita.GET, "/", nil)
+	req.Header.Set(akita.HeaderIfNoneMatch, `"stale"`)
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "test", rec.Body.String())
+	}
+}
+
+func TestETagWeak(t *testing.T) {
+	a := akita.New()
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	h := ETagWithConfig(ETagConfig{Weak: true})(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})
+	if assert.NoError(t, h(ctx)) {
+		assert.True(t, strings.HasPrefix(rec.Header().Get(akita.HeaderETag), `W/"`))
+	}
+}
+
+func TestETagLimit(t *testing.T) {
+	a := akita.New()
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	h := ETagWithConfig(ETagConfig{Limit: "2B"})(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "test", rec.Body.String())
+		assert.Empty(t, rec.Header().Get(akita.HeaderETag))
+	}
+}
@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// IdempotencyConfig defines the config for Idempotency middleware.
+	IdempotencyConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// KeyHeader is the request header holding the idempotency key. Requests
+		// without it are handled as usual.
+		// Optional. Default value "Idempotency-Key".
+		KeyHeader string `json:"key_header"`
+
+		// TTL is the time the response of a key is replayed.
+		// Optional. Default value 24 hours.
+		TTL time.Duration `json:"ttl"`
+
+		// KeyFunc returns the key the response of a request is stored under,
+		// given its idempotency key. The idempotency keys being scoped only to
+		// the method and path by default, they must be unique across the
+		// clients, otherwise KeyFunc should include the identity of the client.
+		// Optional. Default value the method, path and idempotency key.
+		KeyFunc func(ctx akita.Context, key string) string
+
+		// Store keeps the responses and the keys in use.
+		// Optional. Default value an in-memory store.
+		Store IdempotencyStore
+
+		// Methods lists the HTTP methods of the requests made idempotent.
+		// Optional. Default value []string{POST}.
+		Methods []string `json:"methods"`
+	}
+
+	// IdempotencyStore stores the responses of the Idempotency middleware. It
+	// must be safe for concurrent use.
+	IdempotencyStore interface {
+		// Acquire reserves key for a request. It returns the response stored
+		// for key if any, otherwise it reports whether the key was reserved,
+		// false meaning a request with this key is in flight.
+		Acquire(key string) (res *CachedResponse, acquired bool)
+
+		// Store saves the response of the request key was reserved for, and
+		// releases it.
+		Store(key string, res *CachedResponse, ttl time.Duration)
+
+		// Release releases key without a response, e.g. when the request
+		// failed, so it can be retried.
+		Release(key string)
+	}
+
+	memoryIdempotencyStore struct {
+		mutex   sync.Mutex
+		entries map[string]*memoryIdempotencyEntry
+		sweep   time.Time
+	}
+
+	memoryIdempotencyEntry struct {
+		res     *CachedResponse
+		expires time.Time
+	}
+)
+
+// HeaderIdempotentReplayed is set on the responses replayed by the Idempotency
+// middleware.
+const HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+var (
+	// DefaultIdempotencyConfig is the default Idempotency middleware config.
+	DefaultIdempotencyConfig = IdempotencyConfig{
+		Skipper:   DefaultSkipper,
+		KeyHeader: "Idempotency-Key",
+		TTL:       24 * time.Hour,
+		KeyFunc: func(ctx akita.Context, key string) string {
+			req := ctx.Request()
+			return req.Method + " " + req.URL.Path + " " + key
+		},
+		Methods: []string{akita.POST},
+	}
+
+	// ErrIdempotencyKeyInUse is returned for the requests with the key of a
+	// request in flight.
+	ErrIdempotencyKeyInUse = akita.NewHTTPError(http.StatusConflict, "A request with this idempotency key is in progress")
+)
+
+// Idempotency returns a middleware which makes POST requests with an
+// `Idempotency-Key` header idempotent: the response to the first request with
+// a key, unless it failed with an error, is stored and replayed to the
+// retries with the same method, path and key, with an
+// `Idempotent-Replayed: true` header. Retries while the first request is in
+// flight get "409 - Conflict". The keys must be unique across the clients,
+// see `IdempotencyConfig#KeyFunc`. As for `Cache()`, only the headers set by
+// the inner handlers are replayed.
+func Idempotency() akita.MiddlewareFunc {
+	return IdempotencyWithConfig(DefaultIdempotencyConfig)
+}
+
+// IdempotencyWithConfig returns an Idempotency middleware with config.
+// See: `Idempotency()`.
+func IdempotencyWithConfig(config IdempotencyConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultIdempotencyConfig.Skipper
+	}
+	if config.KeyHeader == "" {
+		config.KeyHeader = DefaultIdempotencyConfig.KeyHeader
+	}
+	if config.TTL == 0 {
+		config.TTL = DefaultIdempotencyConfig.TTL
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = DefaultIdempotencyConfig.KeyFunc
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryIdempotencyStore()
+	}
+	if len(config.Methods) == 0 {
+		config.Methods = DefaultIdempotencyConfig.Methods
+	}
+
+	methods := map[string]bool{}
+	for _, m := range config.Methods {
+		methods[strings.ToUpper(m)] = true
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			req := ctx.Request()
+			k := req.Header.Get(config.KeyHeader)
+			if config.Skipper(ctx) || k == "" || !methods[req.Method] {
+				return next(ctx)
+			}
+
+			key := config.KeyFunc(ctx, k)
+			stored, acquired := config.Store.Acquire(key)
+			if stored != nil {
+				res := ctx.Response()
+				for k, v := range stored.Header {
+					res.Header()[k] = append([]string(nil), v...)
+				}
+				res.Header().Set(HeaderIdempotentReplayed, "true")
+				res.WriteHeader(stored.Status)
+				_, err := res.Write(stored.Body)
+				return err
+			}
+			if !acquired {
+				return ErrIdempotencyKeyInUse
+			}
+
+			// Response
+			res := ctx.Response()
+			body := new(bytes.Buffer)
+			rw := res.Writer
+			res.Writer = &cacheResponseWriter{Writer: io.MultiWriter(rw, body), ResponseWriter: rw}
+			before := cloneHeader(res.Header())
+			defer func() {
+				res.Writer = rw
+				// Release the key if the handler panicked
+				if stored == nil {
+					config.Store.Release(key)
+				}
+			}()
+
+			if err := next(ctx); err != nil || !res.Committed {
+				return err
+			}
+			stored = &CachedResponse{
+				Status: res.Status,
+				Header: headerChanges(before, res.Header()),
+				Body:   body.Bytes(),
+			}
+			config.Store.Store(key, stored, config.TTL)
+			return nil
+		}
+	}
+}
+
+// NewMemoryIdempotencyStore returns an IdempotencyStore keeping the responses
+// in memory.
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{entries: map[string]*memoryIdempotencyEntry{}}
+}
+
+func (s *memoryIdempotencyStore) Acquire(key string) (*CachedResponse, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if e, ok := s.entries[key]; ok {
+		if e.res == nil || time.Now().Before(e.expires) {
+			return e.res, false
+		}
+	}
+	s.entries[key] = &memoryIdempotencyEntry{}
+	return nil, true
+}
+
+func (s *memoryIdempotencyStore) Store(key string, res *CachedResponse, ttl time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	s.entries[key] = &memoryIdempotencyEntry{res: res, expires: now.Add(ttl)}
+	// Drop the responses never looked up again, at most once a minute
+	if now.After(s.sweep) {
+		for k, e := range s.entries {
+			if e.res != nil && now.After(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.sweep = now.Add(time.Minute)
+	}
+}
+
+func (s *memoryIdempotencyStore) Release(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if e, ok := s.entries[key]; ok && e.res == nil {
+		delete(s.entries, key)
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	a := akita.New()
+	a.Use(Idempotency())
+	calls := 0
+	a.POST("/payments", func(ctx akita.Context) error {
+		calls++
+		ctx.Response().Header().Set("X-Payment", fmt.Sprint(calls))
+		return ctx.String(http.StatusCreated, fmt.Sprintf("payment %d", calls))
+	})
+	fail := true
+	a.POST("/refunds", func(ctx akita.Context) error {
+		calls++
+		if fail {
+			fail = false
+			return errors.New("gateway down")
+		}
+		return ctx.String(http.StatusCreated, "refund")
+	})
+
+	post := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(akita.POST, path, nil)
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post("/payments", "abc")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "payment 1", rec.Body.String())
+	assert.Empty(t, rec.Header().Get(HeaderIdempotentReplayed))
+
+	// Replayed
+	rec = post("/payments", "abc")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "payment 1", rec.Body.String())
+	assert.Equal(t, "1", rec.Header().Get("X-Payment"))
+	assert.Equal(t, "true", rec.Header().Get(HeaderIdempotentReplayed))
+	assert.Equal(t, 1, calls)
+
+	// Other key, no key
+	assert.Equal(t, "payment 2", post("/payments", "def").Body.String())
+	assert.Equal(t, "payment 3", post("/payments", "").Body.String())
+
+	// Failed requests can be retried
+	assert.Equal(t, http.StatusInternalServerError, post("/refunds", "abc").Code)
+	rec = post("/refunds", "abc")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "refund", rec.Body.String())
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	a := akita.New()
+	a.Use(IdempotencyWithConfig(IdempotencyConfig{KeyHeader: "X-Request-Key", TTL: time.Minute}))
+	started := make(chan struct{})
+	release := make(chan struct{})
+	a.POST("/", func(ctx akita.Context) error {
+		close(started)
+		<-release
+		return ctx.String(http.StatusOK, "done")
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		req := httptest.NewRequest(akita.POST, "/", nil)
+		req.Header.Set("X-Request-Key", "abc")
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		done <- rec
+	}()
+	<-started
+
+	req := httptest.NewRequest(akita.POST, "/", nil)
+	req.Header.Set("X-Request-Key", "abc")
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	close(release)
+	rec = <-done
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "done", rec.Body.String())
+}
+
+func TestIdempotencyKeyFunc(t *testing.T) {
+	a := akita.New()
+	a.Use(IdempotencyWithConfig(IdempotencyConfig{
+		KeyFunc: func(ctx akita.Context, key string) string {
+			return ctx.Request().Header.Get("X-User") + " " + key
+		},
+	}))
+	calls := 0
+	a.POST("/", func(ctx akita.Context) error {
+		calls++
+		return ctx.String(http.StatusOK, fmt.Sprint(calls))
+	})
+
+	post := func(user string) string {
+		req := httptest.NewRequest(akita.POST, "/", nil)
+		req.Header.Set("Idempotency-Key", "abc")
+		req.Header.Set("X-User", user)
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+	assert.Equal(t, "1", post("alice"))
+	assert.Equal(t, "2", post("bob"))
+	assert.Equal(t, "1", post("alice"))
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	s := NewMemoryIdempotencyStore()
+	res, acquired := s.Acquire("a")
+	assert.Nil(t, res)
+	assert.True(t, acquired)
+	s.Store("a", &CachedResponse{Status: http.StatusOK}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	// Expired
+	res, acquired = s.Acquire("a")
+	assert.Nil(t, res)
+	assert.True(t, acquired)
+	_, acquired = s.Acquire("a")
+	assert.False(t, acquired)
+	s.Release("a")
+	_, acquired = s.Acquire("a")
+	assert.True(t, acquired)
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// LocaleConfig defines the config for Locale middleware.
+	LocaleConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Supported is the list of locales supported by the application, e.g.
+		// []string{"en", "fr-CA"}.
+		// Required.
+		Supported []string `json:"supported"`
+
+		// Default is the locale used when no supported locale matches.
+		// Optional. Default value the first supported locale.
+		Default string `json:"default"`
+
+		// Lookup is a comma separated list of "<source>:<key>" used to look up
+		// the locale, in order. The first one matching a supported locale wins.
+		// Possible values:
+		// - "query:<name>"
+		// - "cookie:<name>"
+		// - "header" (`Accept-Language`, honoring quality values)
+		// Optional. Default value "query:lang,cookie:lang,header".
+		Lookup string `json:"lookup"`
+
+		// ContextKey is the key used to store the locale in the context.
+		// Optional. Default value "locale".
+		ContextKey string `json:"context_key"`
+	}
+
+	localeExtractor func(akita.Context) []string
+
+	acceptLanguage struct {
+		tag string
+		q   float64
+	}
+
+	// byQuality sorts the accepted languages by decreasing quality value.
+	byQuality []acceptLanguage
+)
+
+var (
+	// DefaultLocaleConfig is the default Locale middleware config.
+	DefaultLocaleConfig = LocaleConfig{
+		Skipper:    DefaultSkipper,
+		Lookup:     "query:lang,cookie:lang,header",
+		ContextKey: "locale",
+	}
+)
+
+// Locale returns a middleware which picks the best locale for the request out
+// of the supported ones and stores it in the context.
+// See: `LocaleConfig`.
+func Locale(supported ...string) akita.MiddlewareFunc {
+	c := DefaultLocaleConfig
+	c.Supported = supported
+	return LocaleWithConfig(c)
+}
+
+// LocaleWithConfig returns a Locale middleware with config.
+// See: `Locale()`.
+func LocaleWithConfig(config LocaleConfig) akita.MiddlewareFunc {
+	// Defaults
+	if len(config.Supported) == 0 {
+		panic("akita: locale middleware requires supported locales")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultLocaleConfig.Skipper
+	}
+	if config.Default == "" {
+		config.Default = config.Supported[0]
+	}
+	if config.Lookup == "" {
+		config.Lookup = DefaultLocaleConfig.Lookup
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultLocaleConfig.ContextKey
+	}
+
+	extractors := []localeExtractor{}
+	for _, lookup := range strings.Split(config.Lookup, ",") {
+		parts := strings.SplitN(strings.TrimSpace(lookup), ":", 2)
+		switch parts[0] {
+		case "query":
+			extractors = append(extractors, localeFromQuery(parts[1]))
+		case "cookie":
+			extractors = append(extractors, localeFromCookie(parts[1]))
+		case "header":
+			extractors = append(extractors, localeFromHeader)
+		}
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			locale := ""
+			for _, extractor := range extractors {
+				if locale = matchLocale(extractor(ctx), config.Supported); locale != "" {
+					break
+				}
+			}
+			if locale == "" {
+				locale = config.Default
+			}
+			ctx.Set(config.ContextKey, locale)
+			return next(ctx)
+		}
+	}
+}
+
+func localeFromQuery(name string) localeExtractor {
+	return func(ctx akita.Context) []string {
+		if l := ctx.QueryParam(name); l != "" {
+			return []string{l}
+		}
+		return nil
+	}
+}
+
+func localeFromCookie(name string) localeExtractor {
+	return func(ctx akita.Context) []string {
+		if cookie, err := ctx.Cookie(name); err == nil && cookie.Value != "" {
+			return []string{cookie.Value}
+		}
+		return nil
+	}
+}
+
+// localeFromHeader returns the tags of the `Accept-Language` header, ordered by
+// quality value. Tags with a quality value of 0 are not acceptable.
+func localeFromHeader(ctx akita.Context) []string {
+	header := ctx.Request().Header.Get(akita.HeaderAcceptLanguage)
+	if header == "" {
+		return nil
+	}
+	langs := []acceptLanguage{}
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		lang := acceptLanguage{tag: strings.TrimSpace(params[0]), q: 1}
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if q, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					lang.q = q
+				}
+			}
+		}
+		if lang.tag != "" && lang.q > 0 {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Stable(byQuality(langs))
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
+}
+
+func (l byQuality) Len() int           { return len(l) }
+func (l byQuality) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l byQuality) Less(i, j int) bool { return l[i].q > l[j].q }
+
+// matchLocale returns the first supported locale matching the tags, either
+// exactly or by base language, e.g. "fr-FR" matches "fr".
+func matchLocale(tags, supported []string) string {
+	for _, tag := range tags {
+		for _, s := range supported {
+			if strings.EqualFold(tag, s) {
+				return s
+			}
+		}
+		base := localeBase(tag)
+		for _, s := range supported {
+			if strings.EqualFold(base, localeBase(s)) {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func localeBase(tag string) string {
+	if i := strings.IndexAny(tag, "-_"); i != -1 {
+		return tag[:i]
+	}
+	return tag
+}
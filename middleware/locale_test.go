@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocale(t *testing.T) {
+	a := akita.New()
+	h := Locale("en", "fr-CA", "de")(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, ctx.Get("locale").(string))
+	})
+
+	tests := []struct {
+		target, header, cookie, locale string
+	}{
+		{"/", "", "", "en"},
+		{"/", "de", "", "de"},
+		{"/", "fr-CA,de;q=0.9", "", "fr-CA"},
+		{"/", "de;q=0.5, fr;q=0.8, en;q=0.1", "", "fr-CA"},
+		{"/", "es, de-AT;q=0.7", "", "de"},
+		{"/", "de;q=0, es", "", "en"},
+		{"/?lang=de", "fr-CA", "", "de"},
+		{"/?lang=es", "fr-CA", "", "fr-CA"},
+		{"/", "fr-CA", "de", "de"},
+		{"/?lang=en", "fr-CA", "de", "en"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(akita.GET, tt.target, nil)
+		if tt.header != "" {
+			req.Header.Set(akita.HeaderAcceptLanguage, tt.header)
+		}
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+		}
+		rec := httptest.NewRecorder()
+		ctx := a.NewContext(req, rec)
+		if assert.NoError(t, h(ctx)) {
+			assert.Equal(t, tt.locale, rec.Body.String(), tt.target+" "+tt.header)
+		}
+	}
+}
+
+func TestLocaleWithConfig(t *testing.T) {
+	a := akita.New()
+	h := LocaleWithConfig(LocaleConfig{
+		Supported:  []string{"en", "de"},
+		Default:    "de",
+		Lookup:     "header",
+		ContextKey: "lang",
+	})(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, ctx.Get("lang").(string))
+	})
+
+	req := httptest.NewRequest(akita.GET, "/?lang=en", nil)
+	req.Header.Set(akita.HeaderAcceptLanguage, "ja")
+	rec := httptest.NewRecorder()
+	if assert.NoError(t, h(a.NewContext(req, rec))) {
+		assert.Equal(t, "de", rec.Body.String())
+	}
+
+	assert.Panics(t, func() {
+		Locale()
+	})
+}
@@ -17,7 +17,8 @@ import (
 type (
 	// LoggerConfig defines the config for Logger middleware.
 	LoggerConfig struct {
-		// Skipper defines a function to skip middleware.
+		// Skipper defines a function to skip middleware. Skipped requests are
+		// not logged, use `PathSkipper()` to skip paths like health checks.
 		Skipper Skipper
 
 		// Tags to constructed the logger format.
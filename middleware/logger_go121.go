@@ -0,0 +1,113 @@
+//go:build go1.21
+// +build go1.21
+
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// SlogLoggerConfig defines the config for SlogLogger middleware.
+	SlogLoggerConfig struct {
+		// Skipper defines a function to skip middleware. Skipped requests are
+		// not logged, use `PathSkipper()` to skip paths like health checks.
+		Skipper Skipper
+
+		// Logger is the logger the requests are logged to.
+		// Optional. Default value slog.Default().
+		Logger *slog.Logger
+
+		// Message is the message of the records.
+		// Optional. Default value "request".
+		Message string `json:"message"`
+	}
+)
+
+var (
+	// DefaultSlogLoggerConfig is the default SlogLogger middleware config.
+	DefaultSlogLoggerConfig = SlogLoggerConfig{
+		Skipper: DefaultSkipper,
+		Message: "request",
+	}
+)
+
+// SlogLogger returns a middleware that logs HTTP requests to l as structured
+// records with the attributes id, remote_ip, host, method, uri, path, status,
+// latency, bytes_in, bytes_out and error when the handler failed. Requests
+// answered with a 5xx status are logged at the error level, 4xx at the warn
+// level and the others at the info level.
+func SlogLogger(l *slog.Logger) akita.MiddlewareFunc {
+	c := DefaultSlogLoggerConfig
+	c.Logger = l
+	return SlogLoggerWithConfig(c)
+}
+
+// SlogLoggerWithConfig returns a SlogLogger middleware with config.
+// See: `SlogLogger()`.
+func SlogLoggerWithConfig(config SlogLoggerConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultSlogLoggerConfig.Skipper
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.Message == "" {
+		config.Message = DefaultSlogLoggerConfig.Message
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			req := ctx.Request()
+			res := ctx.Response()
+			start := time.Now()
+			err := next(ctx)
+			if err != nil {
+				ctx.Error(err)
+			}
+			latency := time.Since(start)
+
+			id := req.Header.Get(akita.HeaderXRequestID)
+			if id == "" {
+				id = res.Header().Get(akita.HeaderXRequestID)
+			}
+			p := req.URL.Path
+			if p == "" {
+				p = "/"
+			}
+			attrs := []slog.Attr{
+				slog.String("id", id),
+				slog.String("remote_ip", ctx.RealIP()),
+				slog.String("host", req.Host),
+				slog.String("method", req.Method),
+				slog.String("uri", req.RequestURI),
+				slog.String("path", p),
+				slog.Int("status", res.Status),
+				slog.Duration("latency", latency),
+				slog.Int64("bytes_in", req.ContentLength),
+				slog.Int64("bytes_out", res.Size),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case res.Status >= 500:
+				level = slog.LevelError
+			case res.Status >= 400:
+				level = slog.LevelWarn
+			}
+			config.Logger.LogAttrs(req.Context(), level, config.Message, attrs...)
+			return nil
+		}
+	}
+}
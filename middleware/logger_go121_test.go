@@ -0,0 +1,58 @@
+//go:build go1.21
+// +build go1.21
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	a := akita.New()
+	a.Use(SlogLogger(slog.New(slog.NewJSONHandler(buf, nil))))
+	a.GET("/users/:id", func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	})
+	a.GET("/fail", func(ctx akita.Context) error {
+		return errors.New("failure")
+	})
+
+	req := httptest.NewRequest(akita.GET, "/users/1?q=a", nil)
+	req.Header.Set(akita.HeaderXRequestID, "abc")
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+
+	record := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "request", record["msg"])
+	assert.Equal(t, "abc", record["id"])
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, "/users/1?q=a", record["uri"])
+	assert.Equal(t, "/users/1", record["path"])
+	assert.Equal(t, float64(200), record["status"])
+	assert.Equal(t, float64(2), record["bytes_out"])
+	assert.Contains(t, record, "latency")
+	assert.NotContains(t, record, "error")
+
+	buf.Reset()
+	req = httptest.NewRequest(akita.GET, "/fail", nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+
+	record = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, float64(500), record["status"])
+	assert.Equal(t, "failure", record["error"])
+}
@@ -137,3 +137,53 @@ func TestLoggerTemplate(t *testing.T) {
 		assert.True(t, strings.Contains(buf.String(), token) == present, "Case: "+token)
 	}
 }
+
+func TestLoggerSkipper(t *testing.T) {
+	a := akita.New()
+	buf := new(bytes.Buffer)
+	a.Use(LoggerWithConfig(LoggerConfig{
+		Skipper: PathSkipper("/healthz"),
+		Format:  "${path}\n",
+		Output:  buf,
+	}))
+	a.GET("/healthz", func(ctx akita.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})
+	a.GET("/users", func(ctx akita.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(akita.GET, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, buf.String())
+
+	req = httptest.NewRequest(akita.GET, "/users", nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, "/users\n", buf.String())
+}
+
+func TestLoggerErrorRequestID(t *testing.T) {
+	a := akita.New()
+	errLog := new(bytes.Buffer)
+	a.Logger.SetOutput(errLog)
+	accessLog := new(bytes.Buffer)
+	a.Use(RequestID(), LoggerWithConfig(LoggerConfig{
+		Format: "id=${id} status=${status}\n",
+		Output: accessLog,
+	}))
+	a.GET("/", func(ctx akita.Context) error {
+		return errors.New("database down")
+	})
+
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	id := rec.Header().Get(akita.HeaderXRequestID)
+	if assert.NotEmpty(t, id) {
+		assert.Equal(t, "id="+id+" status=500\n", accessLog.String())
+		assert.Contains(t, errLog.String(), "id="+id+": database down")
+	}
+}
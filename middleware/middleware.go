@@ -1,6 +1,10 @@
 package middleware
 
-import "github.com/itchenyi/akita"
+import (
+	"strings"
+
+	"github.com/itchenyi/akita"
+)
 
 type (
 	// Skipper defines a function to skip middleware. Returning true skips processing
@@ -12,3 +16,35 @@ type (
 func DefaultSkipper(akita.Context) bool {
 	return false
 }
+
+// PathSkipper returns a Skipper which skips the middleware for requests to one
+// of the provided paths, e.g. `PathSkipper("/healthz")` to keep health checks
+// out of the logs.
+func PathSkipper(paths ...string) Skipper {
+	skip := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		skip[p] = true
+	}
+	return func(c akita.Context) bool {
+		return skip[c.Request().URL.Path]
+	}
+}
+
+// ForMethods wraps mw so it only runs for requests with one of the provided
+// methods, the others go straight to the next handler, e.g.
+// `a.Use(ForMethods(CSRF(), akita.POST, akita.PUT, akita.PATCH, akita.DELETE))`.
+func ForMethods(mw akita.MiddlewareFunc, methods ...string) akita.MiddlewareFunc {
+	only := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		only[strings.ToUpper(m)] = true
+	}
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		h := mw(next)
+		return func(c akita.Context) error {
+			if only[c.Request().Method] {
+				return h(c)
+			}
+			return next(c)
+		}
+	}
+}
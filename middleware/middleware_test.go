@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForMethods(t *testing.T) {
+	a := akita.New()
+	mw := func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			ctx.Response().Header().Set("X-Inner", "1")
+			return next(ctx)
+		}
+	}
+	a.Use(ForMethods(mw, akita.POST, "put"))
+	h := func(ctx akita.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	}
+	a.GET("/", h)
+	a.POST("/", h)
+	a.PUT("/", h)
+
+	for method, inner := range map[string]string{
+		akita.GET:  "",
+		akita.POST: "1",
+		akita.PUT:  "1",
+	} {
+		req := httptest.NewRequest(method, "/", nil)
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, inner, rec.Header().Get("X-Inner"), method)
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// StripPrefixConfig defines the config for StripPrefix middleware.
+	StripPrefixConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Prefix is removed from the request path before routing, e.g. "/service"
+		// to serve "/service/users" with the route "/users".
+		// Required.
+		Prefix string `json:"prefix"`
+	}
+)
+
+var (
+	// DefaultStripPrefixConfig is the default StripPrefix middleware config.
+	DefaultStripPrefixConfig = StripPrefixConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// StripPrefix returns a root level (before router) middleware which removes
+// the prefix from the request `URL#Path` so an application can be mounted
+// under a path. The path is restored once the request is handled. Requests
+// outside of the prefix are answered with "404 - Not Found".
+//
+// Usage `Akita#Pre(StripPrefix("/service"))`
+func StripPrefix(prefix string) akita.MiddlewareFunc {
+	c := DefaultStripPrefixConfig
+	c.Prefix = prefix
+	return StripPrefixWithConfig(c)
+}
+
+// StripPrefixWithConfig returns a StripPrefix middleware with config.
+// See `StripPrefix()`.
+func StripPrefixWithConfig(config StripPrefixConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultStripPrefixConfig.Skipper
+	}
+	prefix := strings.TrimSuffix(config.Prefix, "/")
+	if prefix == "" {
+		panic("akita: strip-prefix middleware requires a prefix")
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			url := ctx.Request().URL
+			path, ok := stripPrefix(url.Path, prefix)
+			if !ok {
+				return akita.ErrNotFound
+			}
+			rawPath := url.RawPath
+			if rawPath != "" {
+				if rawPath, ok = stripPrefix(rawPath, prefix); !ok {
+					return akita.ErrNotFound
+				}
+			}
+
+			// Restore
+			defer func(path, rawPath string) {
+				url.Path = path
+				url.RawPath = rawPath
+			}(url.Path, url.RawPath)
+
+			url.Path = path
+			url.RawPath = rawPath
+			return next(ctx)
+		}
+	}
+}
+
+// stripPrefix removes the prefix from the path if it's followed by a slash or
+// is the whole path.
+func stripPrefix(path, prefix string) (string, bool) {
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	path = path[len(prefix):]
+	if path == "" {
+		return "/", true
+	}
+	if path[0] != '/' {
+		return "", false
+	}
+	return path, true
+}
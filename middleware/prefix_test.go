@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripPrefix(t *testing.T) {
+	a := akita.New()
+	a.Pre(StripPrefix("/service/"))
+	a.GET("/", func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "index")
+	})
+	a.GET("/users/:id", func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, ctx.Param("id"))
+	})
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/service", http.StatusOK, "index"},
+		{"/service/", http.StatusOK, "index"},
+		{"/service/users/1", http.StatusOK, "1"},
+		{"/users/1", http.StatusNotFound, ""},
+		{"/services/users/1", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(akita.GET, tt.path, nil)
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		assert.Equal(t, tt.code, rec.Code, tt.path)
+		if tt.body != "" {
+			assert.Equal(t, tt.body, rec.Body.String(), tt.path)
+		}
+		assert.Equal(t, tt.path, req.URL.Path)
+	}
+}
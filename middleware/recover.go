@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
 	"runtime"
 
 	"github.com/itchenyi/akita"
@@ -40,7 +41,11 @@ var (
 )
 
 // Recover returns a middleware which recovers from panics anywhere in the chain
-// and handles the control to the centralized HTTPErrorHandler.
+// and handles the control to the centralized HTTPErrorHandler. The panic is
+// logged along with the method, URI, route path, request ID and real IP of the
+// request which caused it. The recovered value is passed on as an
+// `*akita.HTTPError` with status 500, the value itself being available as
+// `HTTPError#Inner`.
 func Recover() akita.MiddlewareFunc {
 	return RecoverWithConfig(DefaultRecoverConfig)
 }
@@ -74,9 +79,17 @@ func RecoverWithConfig(config RecoverConfig) akita.MiddlewareFunc {
 					stack := make([]byte, config.StackSize)
 					length := runtime.Stack(stack, !config.DisableStackAll)
 					if !config.DisablePrintStack {
-						ctx.Logger().Printf("[%s] %s %s\n", color.Red("PANIC RECOVER"), err, stack[:length])
+						req := ctx.Request()
+						id := req.Header.Get(akita.HeaderXRequestID)
+						if id == "" {
+							id = ctx.Response().Header().Get(akita.HeaderXRequestID)
+						}
+						ctx.Logger().Printf("[%s] method=%s uri=%s route=%s id=%s remote_ip=%s: %s %s\n",
+							color.Red("PANIC RECOVER"), req.Method, req.RequestURI, ctx.Path(), id, ctx.RealIP(), err, stack[:length])
 					}
-					ctx.Error(err)
+					he := akita.NewHTTPError(http.StatusInternalServerError)
+					he.Inner = err
+					ctx.Error(he)
 				}
 			}()
 			return next(ctx)
@@ -24,3 +24,46 @@ func TestRecover(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	assert.Contains(t, buf.String(), "PANIC RECOVER")
 }
+
+func TestRecoverRequestContext(t *testing.T) {
+	a := akita.New()
+	buf := new(bytes.Buffer)
+	a.Logger.SetOutput(buf)
+	a.Use(Recover())
+	a.GET("/users/:id", func(ctx akita.Context) error {
+		panic("test")
+	})
+	req := httptest.NewRequest(akita.GET, "/users/1?full=1", nil)
+	req.Header.Set(akita.HeaderXRequestID, "abc")
+	req.RemoteAddr = "203.0.113.1:1234"
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	out := buf.String()
+	assert.Contains(t, out, "PANIC RECOVER")
+	assert.Contains(t, out, "method=GET uri=/users/1?full=1 route=/users/:id id=abc remote_ip=203.0.113.1: test")
+}
+
+func TestRecoverHTTPErrorHandler(t *testing.T) {
+	a := akita.New()
+	a.Logger.SetOutput(new(bytes.Buffer))
+	var handled error
+	a.HTTPErrorHandler = func(err error, ctx akita.Context) {
+		handled = err
+		ctx.String(http.StatusServiceUnavailable, "custom")
+	}
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	h := Recover()(akita.HandlerFunc(func(ctx akita.Context) error {
+		panic("test")
+	}))
+	h(ctx)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "custom", rec.Body.String())
+	if assert.IsType(t, new(akita.HTTPError), handled) {
+		he := handled.(*akita.HTTPError)
+		assert.Equal(t, http.StatusInternalServerError, he.Code)
+		assert.EqualError(t, he.Inner, "test")
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/itchenyi/akita"
 	"github.com/itchenyi/common/random"
 )
@@ -14,14 +16,21 @@ type (
 		// Generator defines a function to generate an ID.
 		// Optional. Default value random.String(32).
 		Generator func() string
+
+		// Context key to store the request ID into context.
+		// Optional. Default value "request_id".
+		ContextKey string `json:"context_key"`
 	}
+
+	requestIDContextKey struct{}
 )
 
 var (
 	// DefaultRequestIDConfig is the default RequestID middleware config.
 	DefaultRequestIDConfig = RequestIDConfig{
-		Skipper:   DefaultSkipper,
-		Generator: generator,
+		Skipper:    DefaultSkipper,
+		Generator:  generator,
+		ContextKey: "request_id",
 	}
 )
 
@@ -39,6 +48,9 @@ func RequestIDWithConfig(config RequestIDConfig) akita.MiddlewareFunc {
 	if config.Generator == nil {
 		config.Generator = generator
 	}
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultRequestIDConfig.ContextKey
+	}
 
 	return func(next akita.HandlerFunc) akita.HandlerFunc {
 		return func(ctx akita.Context) error {
@@ -54,11 +66,23 @@ func RequestIDWithConfig(config RequestIDConfig) akita.MiddlewareFunc {
 			}
 			res.Header().Set(akita.HeaderXRequestID, rid)
 
+			// Store request ID in the context
+			ctx.Set(config.ContextKey, rid)
+			ctx.SetRequest(req.WithContext(context.WithValue(req.Context(), requestIDContextKey{}, rid)))
+
 			return next(ctx)
 		}
 	}
 }
 
+// RequestIDFromContext returns the request ID stored in `context.Context` by
+// RequestID middleware, so that it can be retrieved by code which only has
+// access to the request context.
+func RequestIDFromContext(c context.Context) string {
+	rid, _ := c.Value(requestIDContextKey{}).(string)
+	return rid
+}
+
 func generator() string {
 	return random.String(32)
 }
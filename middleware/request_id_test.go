@@ -31,3 +31,41 @@ func TestRequestID(t *testing.T) {
 	h(ctx)
 	assert.Equal(t, rec.Header().Get(akita.HeaderXRequestID), "customGenerator")
 }
+
+func TestRequestIDContext(t *testing.T) {
+	a := akita.New()
+	handler := func(ctx akita.Context) error {
+		assert.Equal(t, ctx.Response().Header().Get(akita.HeaderXRequestID), ctx.Get("request_id"))
+		assert.Equal(t, ctx.Get("request_id"), RequestIDFromContext(ctx.Request().Context()))
+		return ctx.String(http.StatusOK, "test")
+	}
+	h := RequestID()(handler)
+
+	// Generated
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Len(t, rec.Header().Get(akita.HeaderXRequestID), 32)
+	}
+
+	// Client supplied
+	req = httptest.NewRequest(akita.GET, "/", nil)
+	req.Header.Set(akita.HeaderXRequestID, "client-id")
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, "client-id", rec.Header().Get(akita.HeaderXRequestID))
+		assert.Equal(t, "client-id", ctx.Get("request_id"))
+	}
+
+	// Custom context key
+	h = RequestIDWithConfig(RequestIDConfig{ContextKey: "rid"})(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, ctx.Get("rid").(string))
+	})
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, "client-id", rec.Body.String())
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// RequireHTTPSConfig defines the config for RequireHTTPS middleware.
+	RequireHTTPSConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// TrustForwardedProto accepts requests with the `X-Forwarded-Proto: https`
+		// header, for servers behind a TLS-terminating proxy. Only enable it if
+		// the proxy sets the header, otherwise clients could spoof it.
+		// Optional. Default value false.
+		TrustForwardedProto bool `json:"trust_forwarded_proto"`
+
+		// HSTSMaxAge sets the `Strict-Transport-Security` header of the accepted
+		// requests, telling browsers how long (in seconds) to only use HTTPS.
+		// Optional. Default value 0, no header.
+		HSTSMaxAge int `json:"hsts_max_age"`
+
+		// HSTSExcludeSubdomains won't include subdomains tag in the
+		// `Strict-Transport-Security` header. It has no effect unless HSTSMaxAge
+		// is set to a non-zero value.
+		// Optional. Default value false.
+		HSTSExcludeSubdomains bool `json:"hsts_exclude_subdomains"`
+	}
+)
+
+var (
+	// DefaultRequireHTTPSConfig is the default RequireHTTPS middleware config.
+	DefaultRequireHTTPSConfig = RequireHTTPSConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// RequireHTTPS returns a middleware which rejects plaintext HTTP requests with
+// "403 - Forbidden". Unlike `HTTPSRedirect()` the request is not redirected,
+// so clients sending it in plaintext get an error instead of silently
+// succeeding on the second try.
+//
+// Usage `Akita#Pre(RequireHTTPS())`
+func RequireHTTPS() akita.MiddlewareFunc {
+	return RequireHTTPSWithConfig(DefaultRequireHTTPSConfig)
+}
+
+// RequireHTTPSWithConfig returns a RequireHTTPS middleware with config.
+// See `RequireHTTPS()`.
+func RequireHTTPSWithConfig(config RequireHTTPSConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequireHTTPSConfig.Skipper
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			secure := ctx.IsTLS()
+			if !secure && config.TrustForwardedProto {
+				proto := ctx.Request().Header.Get(akita.HeaderXForwardedProto)
+				secure = strings.EqualFold(proto, "https")
+			}
+			if !secure {
+				return akita.NewHTTPError(http.StatusForbidden, "HTTPS required")
+			}
+
+			if config.HSTSMaxAge != 0 {
+				subdomains := ""
+				if !config.HSTSExcludeSubdomains {
+					subdomains = "; includeSubdomains"
+				}
+				ctx.Response().Header().Set(akita.HeaderStrictTransportSecurity, fmt.Sprintf("max-age=%d%s", config.HSTSMaxAge, subdomains))
+			}
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireHTTPS(t *testing.T) {
+	a := akita.New()
+	h := RequireHTTPSWithConfig(RequireHTTPSConfig{HSTSMaxAge: 3600})(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})
+
+	// Plaintext
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	he := h(ctx).(*akita.HTTPError)
+	assert.Equal(t, http.StatusForbidden, he.Code)
+
+	// Forwarded header not trusted
+	req = httptest.NewRequest(akita.GET, "/", nil)
+	req.Header.Set(akita.HeaderXForwardedProto, "https")
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	he = h(ctx).(*akita.HTTPError)
+	assert.Equal(t, http.StatusForbidden, he.Code)
+
+	// TLS
+	req = httptest.NewRequest(akita.GET, "/", nil)
+	req.TLS = &tls.ConnectionState{}
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "max-age=3600; includeSubdomains", rec.Header().Get(akita.HeaderStrictTransportSecurity))
+	}
+}
+
+func TestRequireHTTPSForwarded(t *testing.T) {
+	a := akita.New()
+	h := RequireHTTPSWithConfig(RequireHTTPSConfig{TrustForwardedProto: true})(func(ctx akita.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})
+
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	req.Header.Set(akita.HeaderXForwardedProto, "https")
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(akita.HeaderStrictTransportSecurity))
+	}
+
+	req = httptest.NewRequest(akita.GET, "/", nil)
+	req.Header.Set(akita.HeaderXForwardedProto, "http")
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	he := h(ctx).(*akita.HTTPError)
+	assert.Equal(t, http.StatusForbidden, he.Code)
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// ServerTimingConfig defines the config for ServerTiming middleware.
+	ServerTimingConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Total adds a "total" metric with the time spent handling the request
+		// until the response is written.
+		// Optional. Default value false.
+		Total bool `json:"total"`
+	}
+
+	serverTiming struct {
+		mutex   sync.Mutex
+		metrics []serverTimingMetric
+	}
+
+	serverTimingMetric struct {
+		name string
+		dur  time.Duration
+	}
+)
+
+// ServerTimingContextKey is the key of the metrics collector stored in the
+// context by the ServerTiming middleware.
+const ServerTimingContextKey = "server_timing"
+
+var (
+	// DefaultServerTimingConfig is the default ServerTiming middleware config.
+	DefaultServerTimingConfig = ServerTimingConfig{
+		Skipper: DefaultSkipper,
+	}
+)
+
+// ServerTiming returns a middleware which sends the durations recorded with
+// `StartTiming()` and `AddTiming()` in the `Server-Timing` header of the
+// response, e.g. `db;dur=53, render;dur=12`. Only the ones recorded before the
+// response is written are sent.
+func ServerTiming() akita.MiddlewareFunc {
+	return ServerTimingWithConfig(DefaultServerTimingConfig)
+}
+
+// ServerTimingWithConfig returns a ServerTiming middleware with config.
+// See: `ServerTiming()`.
+func ServerTimingWithConfig(config ServerTimingConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultServerTimingConfig.Skipper
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			start := time.Now()
+			st := new(serverTiming)
+			ctx.Set(ServerTimingContextKey, st)
+			res := ctx.Response()
+			res.Before(func() {
+				if config.Total {
+					st.add("total", time.Since(start))
+				}
+				if h := st.header(); h != "" {
+					res.Header().Set(akita.HeaderServerTiming, h)
+				}
+			})
+			return next(ctx)
+		}
+	}
+}
+
+// StartTiming starts timing the named metric of the request and returns the
+// function stopping it. The name must be a token, e.g. "db" or "cache-read".
+// It does nothing unless the ServerTiming middleware is used.
+func StartTiming(ctx akita.Context, name string) (stop func()) {
+	st, ok := ctx.Get(ServerTimingContextKey).(*serverTiming)
+	if !ok {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		st.add(name, time.Since(start))
+	}
+}
+
+// AddTiming records the duration of the named metric of the request, see
+// `StartTiming()`.
+func AddTiming(ctx akita.Context, name string, dur time.Duration) {
+	if st, ok := ctx.Get(ServerTimingContextKey).(*serverTiming); ok {
+		st.add(name, dur)
+	}
+}
+
+func (st *serverTiming) add(name string, dur time.Duration) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.metrics = append(st.metrics, serverTimingMetric{name, dur})
+}
+
+func (st *serverTiming) header() string {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	metrics := make([]string, len(st.metrics))
+	for i, m := range st.metrics {
+		ms := float64(m.dur/time.Microsecond) / 1000
+		metrics[i] = m.name + ";dur=" + strconv.FormatFloat(ms, 'f', -1, 64)
+	}
+	return strings.Join(metrics, ", ")
+}
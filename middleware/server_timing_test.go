@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerTiming(t *testing.T) {
+	a := akita.New()
+	a.Use(ServerTimingWithConfig(ServerTimingConfig{Total: true}))
+	a.GET("/", func(ctx akita.Context) error {
+		stop := StartTiming(ctx, "db")
+		time.Sleep(5 * time.Millisecond)
+		stop()
+		AddTiming(ctx, "render", 12*time.Millisecond)
+		return ctx.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	h := rec.Header().Get(akita.HeaderServerTiming)
+	assert.Regexp(t, regexp.MustCompile(`^db;dur=\d+(\.\d+)?, render;dur=12, total;dur=\d+(\.\d+)?$`), h)
+}
+
+func TestServerTimingWithoutMiddleware(t *testing.T) {
+	a := akita.New()
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	assert.NotPanics(t, func() {
+		StartTiming(ctx, "db")()
+		AddTiming(ctx, "render", time.Millisecond)
+	})
+	ctx.NoContent(http.StatusOK)
+	assert.Empty(t, rec.Header().Get(akita.HeaderServerTiming))
+}
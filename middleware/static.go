@@ -2,11 +2,14 @@ package middleware
 
 import (
 	"fmt"
+	"html/template"
+	"mime"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/itchenyi/akita"
 )
@@ -33,9 +36,54 @@ type (
 		// Enable directory browsing.
 		// Optional. Default value false.
 		Browse bool `json:"browse"`
+
+		// CacheControl is the value of the `Cache-Control` header sent with the
+		// served files, e.g. "public, max-age=31536000" for immutable assets.
+		// Optional. Default value none.
+		CacheControl string `json:"cache_control"`
+
+		// Precompressed serves `<file>.gz` with `Content-Encoding: gzip` in place
+		// of the requested file when it exists and the client accepts gzip.
+		// Optional. Default value false.
+		Precompressed bool `json:"precompressed"`
+
+		// NegotiateImages serves the WebP sibling of a JPEG, PNG or GIF image,
+		// e.g. `photo.webp` for `photo.jpg`, in place of the requested file when
+		// it exists and the client accepts `image/webp`.
+		// Optional. Default value false.
+		NegotiateImages bool `json:"negotiate_images"`
+
+		// NotFoundFile is the file, relative to Root, served with status 404 when
+		// the requested file doesn't exist, e.g. "404.html". `HTML5` takes
+		// precedence.
+		// Optional. Default value none, the error is handled by the
+		// HTTPErrorHandler.
+		NotFoundFile string `json:"not_found_file"`
+
+		// Template renders the directory listing when `Browse` is enabled. It is
+		// executed with a `StaticDirListing`.
+		// Optional. Default value renders a plain list of links.
+		Template *template.Template
+	}
+
+	// StaticDirListing is the data used to render a directory listing.
+	StaticDirListing struct {
+		// Path is the request path of the directory.
+		Path  string
+		Files []StaticDirEntry
+	}
+
+	// StaticDirEntry is a file of a directory listing.
+	StaticDirEntry struct {
+		Name    string
+		Size    int64
+		ModTime time.Time
+		IsDir   bool
 	}
 )
 
+const mimeImageWebP = "image/webp"
+
 var (
 	// DefaultStaticConfig is the default Static middleware config.
 	DefaultStaticConfig = StaticConfig{
@@ -91,7 +139,7 @@ func StaticWithConfig(config StaticConfig) akita.MiddlewareFunc {
 								return ctx.File(filepath.Join(config.Root, config.Index))
 							}
 						}
-						return
+						return staticNotFound(ctx, err, config)
 					}
 				}
 				return
@@ -103,34 +151,131 @@ func StaticWithConfig(config StaticConfig) akita.MiddlewareFunc {
 
 				if err != nil {
 					if config.Browse {
-						return listDir(name, ctx.Response())
+						return listDir(name, ctx, config.Template)
 					}
 					if os.IsNotExist(err) {
-						return next(ctx)
+						return staticNotFound(ctx, next(ctx), config)
 					}
 					return
 				}
 
-				return ctx.File(index)
+				return serveFile(ctx, index, config)
 			}
 
-			return ctx.File(name)
+			return serveFile(ctx, name, config)
 		}
 	}
 }
 
-func listDir(name string, res *akita.Response) (err error) {
+// staticNotFound serves the `NotFoundFile` of the config in place of the not
+// found error err, other errors being returned as is.
+func staticNotFound(ctx akita.Context, err error, config StaticConfig) error {
+	if he, ok := err.(*akita.HTTPError); ok && he.Code == http.StatusNotFound && config.NotFoundFile != "" {
+		return ctx.FileWithStatus(filepath.Join(config.Root, config.NotFoundFile), http.StatusNotFound)
+	}
+	return err
+}
+
+func serveFile(ctx akita.Context, name string, config StaticConfig) (err error) {
+	if config.CacheControl != "" {
+		ctx.CacheControl(config.CacheControl)
+	}
+	if config.NegotiateImages {
+		if webp, ok := negotiateImage(ctx, name); ok {
+			ctx.Response().Header().Set(akita.HeaderContentType, mimeImageWebP)
+			name = webp
+		}
+	}
+	if config.Precompressed {
+		var ok bool
+		if ok, err = servePrecompressed(ctx, name); ok {
+			return
+		}
+	}
+	if err = ctx.File(name); err != nil {
+		ctx.Response().Header().Del(akita.HeaderCacheControl)
+	}
+	return
+}
+
+// negotiateImage returns the WebP sibling of the image file if there is one and
+// the client accepts WebP.
+func negotiateImage(ctx akita.Context, name string) (string, bool) {
+	ext := filepath.Ext(name)
+	switch strings.ToLower(ext) {
+	case ".jpg", ".jpeg", ".png", ".gif":
+	default:
+		return "", false
+	}
+	ctx.Response().Header().Add(akita.HeaderVary, akita.HeaderAccept)
+	if !strings.Contains(ctx.Request().Header.Get(akita.HeaderAccept), mimeImageWebP) {
+		return "", false
+	}
+	webp := strings.TrimSuffix(name, ext) + ".webp"
+	if fi, err := os.Stat(webp); err != nil || fi.IsDir() {
+		return "", false
+	}
+	return webp, true
+}
+
+// servePrecompressed serves the gzip sibling of the file if there is one and
+// the client accepts it. It reports whether the response was handled.
+func servePrecompressed(ctx akita.Context, name string) (bool, error) {
+	res := ctx.Response()
+	res.Header().Add(akita.HeaderVary, akita.HeaderAcceptEncoding)
+	if !strings.Contains(ctx.Request().Header.Get(akita.HeaderAcceptEncoding), gzipScheme) {
+		return false, nil
+	}
+	f, err := os.Open(name + ".gz")
+	if err != nil {
+		return false, nil
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		return false, nil
+	}
+
+	// Content type of the original file, not of the archive
+	ext := filepath.Ext(name)
+	ct, ok := ctx.Akita().MIMETypes[strings.ToLower(ext)]
+	if !ok {
+		ct = mime.TypeByExtension(ext)
+	}
+	if ct == "" {
+		ct = akita.MIMEOctetStream
+	}
+	res.Header().Set(akita.HeaderContentType, ct)
+	res.Header().Set(akita.HeaderContentEncoding, gzipScheme)
+	return true, ctx.ServeReader(filepath.Base(name), fi.ModTime(), f)
+}
+
+func listDir(name string, ctx akita.Context, tmpl *template.Template) (err error) {
 	dir, err := os.Open(name)
 	if err != nil {
 		return
 	}
+	defer dir.Close()
 	dirs, err := dir.Readdir(-1)
 	if err != nil {
 		return
 	}
 
 	// Create a directory index
+	res := ctx.Response()
 	res.Header().Set(akita.HeaderContentType, akita.MIMETextHTMLCharsetUTF8)
+	if tmpl != nil {
+		listing := StaticDirListing{Path: ctx.Request().URL.Path}
+		for _, d := range dirs {
+			listing.Files = append(listing.Files, StaticDirEntry{
+				Name:    d.Name(),
+				Size:    d.Size(),
+				ModTime: d.ModTime(),
+				IsDir:   d.IsDir(),
+			})
+		}
+		return tmpl.Execute(res, listing)
+	}
 	if _, err = fmt.Fprintf(res, "<pre>\n"); err != nil {
 		return
 	}
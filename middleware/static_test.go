@@ -1,8 +1,14 @@
 package middleware
 
 import (
+	"bytes"
+	"compress/gzip"
+	"html/template"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/itchenyi/akita"
@@ -65,3 +71,176 @@ func TestStatic(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), "cert.pem")
 	}
 }
+
+func TestStaticNotFoundFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "akita-static")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(root)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "404.html"), []byte("<h1>Lost?</h1>"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "app.js"), []byte("app()"), 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "empty"), 0755))
+
+	a := akita.New()
+	h := StaticWithConfig(StaticConfig{Root: root, NotFoundFile: "404.html"})(akita.NotFoundHandler)
+	for _, p := range []string{"/missing.css", "/empty"} {
+		req := httptest.NewRequest(akita.GET, p, nil)
+		rec := httptest.NewRecorder()
+		ctx := a.NewContext(req, rec)
+		if assert.NoError(t, h(ctx), p) {
+			assert.Equal(t, http.StatusNotFound, rec.Code, p)
+			assert.Contains(t, rec.Header().Get(akita.HeaderContentType), akita.MIMETextHTML, p)
+			assert.Equal(t, "<h1>Lost?</h1>", rec.Body.String(), p)
+		}
+	}
+
+	// Found
+	req := httptest.NewRequest(akita.GET, "/app.js", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "app()", rec.Body.String())
+	}
+}
+
+func TestStaticCacheControl(t *testing.T) {
+	a := akita.New()
+	config := StaticConfig{
+		Root:         "../_fixture",
+		CacheControl: "public, max-age=31536000",
+	}
+	h := StaticWithConfig(config)(akita.NotFoundHandler)
+
+	// File found
+	req := httptest.NewRequest(akita.GET, "/images/akita.png", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, max-age=31536000", rec.Header().Get(akita.HeaderCacheControl))
+	}
+
+	// File not found
+	req = httptest.NewRequest(akita.GET, "/none", nil)
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	a.HTTPErrorHandler(h(ctx), ctx)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get(akita.HeaderCacheControl))
+}
+
+func TestStaticBrowseTemplate(t *testing.T) {
+	a := akita.New()
+	tmpl := template.Must(template.New("listing").Parse(
+		`<h1>{{.Path}}</h1>{{range .Files}}<li>{{.Name}}{{if .IsDir}}/{{end}} {{.Size}}</li>{{end}}`))
+	h := StaticWithConfig(StaticConfig{
+		Root:     "../_fixture",
+		Browse:   true,
+		Template: tmpl,
+	})(akita.NotFoundHandler)
+
+	req := httptest.NewRequest(akita.GET, "/certs", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, akita.MIMETextHTMLCharsetUTF8, rec.Header().Get(akita.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "<h1>/certs</h1>")
+		assert.Contains(t, rec.Body.String(), "<li>cert.pem ")
+	}
+}
+
+func TestStaticPrecompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "akita-static")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	js := []byte("console.log('akita')")
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	w.Write(js)
+	w.Close()
+	ioutil.WriteFile(filepath.Join(dir, "app.js"), js, 0644)
+	ioutil.WriteFile(filepath.Join(dir, "app.js.gz"), buf.Bytes(), 0644)
+
+	a := akita.New()
+	h := StaticWithConfig(StaticConfig{
+		Root:          dir,
+		Precompressed: true,
+	})(akita.NotFoundHandler)
+
+	// Gzip accepted
+	req := httptest.NewRequest(akita.GET, "/app.js", nil)
+	req.Header.Set(akita.HeaderAcceptEncoding, gzipScheme)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, gzipScheme, rec.Header().Get(akita.HeaderContentEncoding))
+		assert.Contains(t, rec.Header().Get(akita.HeaderContentType), "javascript")
+		assert.Equal(t, buf.Bytes(), rec.Body.Bytes())
+	}
+
+	// Gzip not accepted
+	req = httptest.NewRequest(akita.GET, "/app.js", nil)
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(akita.HeaderContentEncoding))
+		assert.Equal(t, js, rec.Body.Bytes())
+	}
+}
+
+func TestStaticNegotiateImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "akita-static")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "photo.jpg"), []byte("jpeg"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "photo.webp"), []byte("webp"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "logo.png"), []byte("png"), 0644)
+
+	a := akita.New()
+	h := StaticWithConfig(StaticConfig{
+		Root:            dir,
+		NegotiateImages: true,
+	})(akita.NotFoundHandler)
+
+	// WebP accepted
+	req := httptest.NewRequest(akita.GET, "/photo.jpg", nil)
+	req.Header.Set(akita.HeaderAccept, "image/avif,image/webp,image/*,*/*;q=0.8")
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/webp", rec.Header().Get(akita.HeaderContentType))
+		assert.Equal(t, akita.HeaderAccept, rec.Header().Get(akita.HeaderVary))
+		assert.Equal(t, "webp", rec.Body.String())
+	}
+
+	// WebP not accepted
+	req = httptest.NewRequest(akita.GET, "/photo.jpg", nil)
+	req.Header.Set(akita.HeaderAccept, "image/*")
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, "image/jpeg", rec.Header().Get(akita.HeaderContentType))
+		assert.Equal(t, akita.HeaderAccept, rec.Header().Get(akita.HeaderVary))
+		assert.Equal(t, "jpeg", rec.Body.String())
+	}
+
+	// No sibling
+	req = httptest.NewRequest(akita.GET, "/logo.png", nil)
+	req.Header.Set(akita.HeaderAccept, "image/webp")
+	rec = httptest.NewRecorder()
+	ctx = a.NewContext(req, rec)
+	if assert.NoError(t, h(ctx)) {
+		assert.Equal(t, "image/png", rec.Header().Get(akita.HeaderContentType))
+		assert.Equal(t, "png", rec.Body.String())
+	}
+}
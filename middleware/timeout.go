@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// TimeoutConfig defines the config for Timeout middleware.
+	TimeoutConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper Skipper
+
+		// Timeout is the time a handler has to complete the request. A route
+		// overrides it with a `time.Duration` stored in its data under
+		// `TimeoutRouteDataKey`.
+		// Optional. Default value 0, meaning no timeout.
+		Timeout time.Duration `json:"timeout"`
+
+		// ErrorMessage is the body sent along with status 503 when the handler
+		// times out.
+		// Optional. Default value "Service Unavailable".
+		ErrorMessage string `json:"error_message"`
+	}
+)
+
+// TimeoutRouteDataKey is the key of the per-route timeout in the route data.
+const TimeoutRouteDataKey = "timeout"
+
+var (
+	// DefaultTimeoutConfig is the default Timeout middleware config.
+	DefaultTimeoutConfig = TimeoutConfig{
+		Skipper:      DefaultSkipper,
+		ErrorMessage: http.StatusText(http.StatusServiceUnavailable),
+	}
+)
+
+// Timeout returns a middleware which responds with "503 - Service Unavailable"
+// if the handler takes longer than timeout. The request context is canceled
+// on timeout and the response written by the handler is discarded. Handlers
+// should return as soon as the context is done, the middleware waits for them
+// before the request completes.
+//
+// Routes may set their own timeout, e.g.
+//
+//	a.GET("/report", h).SetData(middleware.TimeoutRouteDataKey, 30*time.Second)
+func Timeout(timeout time.Duration) akita.MiddlewareFunc {
+	c := DefaultTimeoutConfig
+	c.Timeout = timeout
+	return TimeoutWithConfig(c)
+}
+
+// TimeoutWithConfig returns a Timeout middleware with config.
+// See: `Timeout()`.
+func TimeoutWithConfig(config TimeoutConfig) akita.MiddlewareFunc {
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultTimeoutConfig.Skipper
+	}
+	if config.ErrorMessage == "" {
+		config.ErrorMessage = DefaultTimeoutConfig.ErrorMessage
+	}
+
+	return func(next akita.HandlerFunc) akita.HandlerFunc {
+		return func(ctx akita.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			timeout := config.Timeout
+			if r := ctx.Route(); r != nil {
+				if d, ok := r.Data[TimeoutRouteDataKey].(time.Duration); ok {
+					timeout = d
+				}
+			}
+			if timeout <= 0 {
+				return next(ctx)
+			}
+
+			// The handler writes to a buffer which is only copied to the
+			// response if it completes in time.
+			res := ctx.Response()
+			w := res.Writer
+			done := make(chan struct{})
+			http.TimeoutHandler(http.HandlerFunc(func(tw http.ResponseWriter, r *http.Request) {
+				defer close(done)
+				res.Writer = tw
+				ctx.SetRequest(r)
+				if err := next(ctx); err != nil {
+					ctx.Error(err)
+				}
+			}), timeout, config.ErrorMessage).ServeHTTP(w, ctx.Request())
+
+			select {
+			case <-done:
+			default:
+				// Timed out. Send the error right away but wait for the handler
+				// before the context is reused.
+				if f, ok := w.(http.Flusher); ok {
+					f.Flush()
+				}
+				<-done
+				res.Status = http.StatusServiceUnavailable
+				res.Committed = true
+			}
+			res.Writer = w
+			return nil
+		}
+	}
+}
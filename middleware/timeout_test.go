@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	a := akita.New()
+	a.Use(Timeout(20 * time.Millisecond))
+	slow := func(ctx akita.Context) error {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Request().Context().Done():
+			return ctx.Request().Context().Err()
+		}
+		return ctx.String(http.StatusOK, "done")
+	}
+	a.GET("/fast", slow)
+	a.GET("/report", slow).SetData(TimeoutRouteDataKey, time.Second)
+
+	// Default timeout
+	req := httptest.NewRequest(akita.GET, "/fast", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, http.StatusText(http.StatusServiceUnavailable), rec.Body.String())
+
+	// Route timeout
+	req = httptest.NewRequest(akita.GET, "/report", nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "done", rec.Body.String())
+}
+
+func TestTimeoutError(t *testing.T) {
+	a := akita.New()
+	a.Use(Timeout(time.Second))
+	a.GET("/", func(ctx akita.Context) error {
+		return akita.ErrForbidden
+	})
+
+	req := httptest.NewRequest(akita.GET, "/", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
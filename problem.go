@@ -0,0 +1,31 @@
+package akita
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Problem describes an error as problem details, see RFC 7807. It is sent with
+// `Context#Problem()` or returned by a handler as an error, in which case
+// `Akita#DefaultHTTPErrorHandler()` sends it.
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// NewProblem creates a Problem for the status code titled with its status text.
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Error makes it compatible with `error` interface.
+func (p *Problem) Error() string {
+	return fmt.Sprintf("status=%d, title=%s, detail=%s", p.Status, p.Title, p.Detail)
+}
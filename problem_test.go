@@ -0,0 +1,48 @@
+package akita
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextProblem(t *testing.T) {
+	a := New()
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := a.NewContext(req, rec)
+
+	err := ctx.Problem(http.StatusForbidden, Problem{
+		Type:     "https://example.com/probs/out-of-credit",
+		Title:    "You do not have enough credit.",
+		Detail:   "Your current balance is 30, but that costs 50.",
+		Instance: "/account/12345/msgs/abc",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(HeaderContentType))
+		assert.JSONEq(t, `{
+			"type": "https://example.com/probs/out-of-credit",
+			"title": "You do not have enough credit.",
+			"status": 403,
+			"detail": "Your current balance is 30, but that costs 50.",
+			"instance": "/account/12345/msgs/abc"
+		}`, rec.Body.String())
+	}
+}
+
+func TestProblemError(t *testing.T) {
+	a := New()
+	a.GET("/", func(ctx Context) error {
+		return NewProblem(http.StatusConflict, "User already exists")
+	})
+
+	req := httptest.NewRequest(GET, "/", nil)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(HeaderContentType))
+	assert.JSONEq(t, `{"title":"Conflict","status":409,"detail":"User already exists"}`, rec.Body.String())
+}
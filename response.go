@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"net"
 	"net/http"
+	"strings"
 )
 
 type (
@@ -17,6 +18,7 @@ type (
 		Status      int
 		Size        int64
 		Committed   bool
+		hijacked    bool
 	}
 )
 
@@ -45,6 +47,10 @@ func (r *Response) Before(fn func()) {
 // WriteHeader(http.StatusOK). Thus explicit calls to WriteHeader are mainly
 // used to send error codes.
 func (r *Response) WriteHeader(code int) {
+	if r.hijacked {
+		r.akita.Logger.Warn("response write header on hijacked connection")
+		return
+	}
 	if r.Committed {
 		r.akita.Logger.Warn("response already committed")
 		return
@@ -52,13 +58,20 @@ func (r *Response) WriteHeader(code int) {
 	for _, fn := range r.beforeFuncs {
 		fn()
 	}
+	if r.akita.NormalizeCharset {
+		normalizeCharset(r.Header())
+	}
 	r.Status = code
 	r.Writer.WriteHeader(code)
 	r.Committed = true
 }
 
 // Write writes the data to the connection as part of an HTTP reply.
+// It returns `http.ErrHijacked` once the connection has been hijacked.
 func (r *Response) Write(b []byte) (n int, err error) {
+	if r.hijacked {
+		return 0, http.ErrHijacked
+	}
 	if !r.Committed {
 		r.WriteHeader(http.StatusOK)
 	}
@@ -71,14 +84,23 @@ func (r *Response) Write(b []byte) (n int, err error) {
 // buffered data to the client.
 // See [http.Flusher](https://golang.org/pkg/net/http/#Flusher)
 func (r *Response) Flush() {
+	if r.hijacked {
+		return
+	}
 	r.Writer.(http.Flusher).Flush()
 }
 
 // Hijack implements the http.Hijacker interface to allow an HTTP handler to
 // take over the connection.
+// Writes to the response once hijacked are discarded.
 // See [http.Hijacker](https://golang.org/pkg/net/http/#Hijacker)
 func (r *Response) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	return r.Writer.(http.Hijacker).Hijack()
+	conn, rw, err := r.Writer.(http.Hijacker).Hijack()
+	if err == nil {
+		r.hijacked = true
+		r.Committed = true
+	}
+	return conn, rw, err
 }
 
 // CloseNotify implements the http.CloseNotifier interface to allow detecting
@@ -95,4 +117,24 @@ func (r *Response) reset(w http.ResponseWriter) {
 	r.Size = 0
 	r.Status = http.StatusOK
 	r.Committed = false
+	r.hijacked = false
+}
+
+// normalizeCharset appends "; charset=UTF-8" to the text based content type
+// of the response if it has no charset, see `Akita#NormalizeCharset`.
+func normalizeCharset(header http.Header) {
+	ct := header.Get(HeaderContentType)
+	if ct == "" || strings.Contains(strings.ToLower(ct), "charset=") {
+		return
+	}
+	mt := strings.ToLower(strings.TrimSpace(strings.SplitN(ct, ";", 2)[0]))
+	switch {
+	case strings.HasPrefix(mt, "text/"),
+		mt == MIMEApplicationJSON,
+		mt == MIMEApplicationJavaScript,
+		mt == MIMEApplicationXML,
+		strings.HasSuffix(mt, "+json"),
+		strings.HasSuffix(mt, "+xml"):
+		header.Set(HeaderContentType, ct+"; "+charsetUTF8)
+	}
 }
@@ -1,6 +1,9 @@
 package akita
 
 import (
+	"bufio"
+	"net"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -21,3 +24,73 @@ func TestResponse(t *testing.T) {
 	res.Write([]byte("test"))
 	assert.Equal(t, "akita", rec.Header().Get(HeaderServer))
 }
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	c, _ := net.Pipe()
+	return c, bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c)), nil
+}
+
+func (r *hijackRecorder) WriteHeader(code int) {
+	if r.hijacked {
+		panic("write header after hijack")
+	}
+	r.ResponseRecorder.WriteHeader(code)
+}
+
+func (r *hijackRecorder) Write(b []byte) (int, error) {
+	if r.hijacked {
+		panic("write after hijack")
+	}
+	return r.ResponseRecorder.Write(b)
+}
+
+func TestResponseHijacked(t *testing.T) {
+	a := New()
+	a.GET("/ws", func(ctx Context) error {
+		conn, _, err := ctx.Response().Hijack()
+		if err != nil {
+			return err
+		}
+		conn.Close()
+		_, err = ctx.Response().Write([]byte("test"))
+		assert.Equal(t, http.ErrHijacked, err)
+		return ctx.String(http.StatusOK, "test")
+	})
+
+	req := httptest.NewRequest(GET, "/ws", nil)
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	assert.NotPanics(t, func() {
+		a.ServeHTTP(rec, req)
+	})
+	assert.True(t, rec.hijacked)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestResponseNormalizeCharset(t *testing.T) {
+	a := New()
+	for _, c := range []struct {
+		normalize bool
+		in, out   string
+	}{
+		{false, MIMEApplicationJSON, MIMEApplicationJSON},
+		{true, MIMEApplicationJSON, MIMEApplicationJSONCharsetUTF8},
+		{true, "text/csv", "text/csv; charset=UTF-8"},
+		{true, "application/vnd.api+json", "application/vnd.api+json; charset=UTF-8"},
+		{true, "text/plain; charset=ISO-8859-1", "text/plain; charset=ISO-8859-1"},
+		{true, MIMEOctetStream, MIMEOctetStream},
+		{true, "image/png", "image/png"},
+	} {
+		a.NormalizeCharset = c.normalize
+		rec := httptest.NewRecorder()
+		res := &Response{akita: a, Writer: rec}
+		res.Header().Set(HeaderContentType, c.in)
+		res.WriteHeader(http.StatusOK)
+		assert.Equal(t, c.out, rec.Header().Get(HeaderContentType), c.in)
+	}
+}
@@ -1,6 +1,10 @@
 package akita
 
-import "strings"
+import (
+	"fmt"
+	"io"
+	"strings"
+)
 
 type (
 	// Router is the registry of all registered routes for an `Akita` instance for
@@ -52,7 +56,8 @@ func NewRouter(a *Akita) *Router {
 	}
 }
 
-// Add registers a new route for method and path with matching handler.
+// Add registers a new route for method and path with matching handler. A colon
+// escaped as `\:` is matched literally, e.g. `/v1/things\:batchGet`.
 func (r *Router) Add(method, path string, h HandlerFunc) {
 	// Validate path
 	if path == "" {
@@ -61,14 +66,20 @@ func (r *Router) Add(method, path string, h HandlerFunc) {
 	if path[0] != '/' {
 		path = "/" + path
 	}
-	ppath := path        // Pristine path
-	pnames := []string{} // Param names
+	ppath := path              // Pristine path
+	pnames := []string{}       // Param names
+	literals := map[int]bool{} // Positions of escaped colons
 
 	for i, l := 0, len(path); i < l; i++ {
-		if path[i] == ':' {
+		if path[i] == '\\' && i+1 < l && path[i+1] == ':' {
+			// Escaped colon, keep it as a literal
+			path = path[:i] + path[i+1:]
+			l--
+			literals[i] = true
+		} else if path[i] == ':' {
 			j := i + 1
 
-			r.insert(method, path[:i], nil, skind, "", nil)
+			r.insert(method, path[:i], nil, skind, "", nil, literals)
 			for ; i < l && path[i] != '/'; i++ {
 			}
 
@@ -77,22 +88,22 @@ func (r *Router) Add(method, path string, h HandlerFunc) {
 			i, l = j, len(path)
 
 			if i == l {
-				r.insert(method, path[:i], h, pkind, ppath, pnames)
+				r.insert(method, path[:i], h, pkind, ppath, pnames, literals)
 				return
 			}
-			r.insert(method, path[:i], nil, pkind, ppath, pnames)
+			r.insert(method, path[:i], nil, pkind, ppath, pnames, literals)
 		} else if path[i] == '*' {
-			r.insert(method, path[:i], nil, skind, "", nil)
+			r.insert(method, path[:i], nil, skind, "", nil, literals)
 			pnames = append(pnames, "*")
-			r.insert(method, path[:i+1], h, akind, ppath, pnames)
+			r.insert(method, path[:i+1], h, akind, ppath, pnames, literals)
 			return
 		}
 	}
 
-	r.insert(method, path, h, skind, ppath, pnames)
+	r.insert(method, path, h, skind, ppath, pnames, literals)
 }
 
-func (r *Router) insert(method, path string, h HandlerFunc, t kind, ppath string, pnames []string) {
+func (r *Router) insert(method, path string, h HandlerFunc, t kind, ppath string, pnames []string, literals map[int]bool) {
 	// Adjust max param
 	l := len(pnames)
 	if *r.akita.maxParam < l {
@@ -157,7 +168,17 @@ func (r *Router) insert(method, path string, h HandlerFunc, t kind, ppath string
 			}
 		} else if l < sl {
 			search = search[l:]
-			c := cn.findChildWithLabel(search[0])
+			var c *node
+			if search[0] == ':' {
+				// Literal colons live in static nodes, param markers in param nodes
+				k := pkind
+				if literals[len(path)-len(search)] {
+					k = skind
+				}
+				c = cn.findChild(':', k)
+			} else {
+				c = cn.findChildWithLabel(search[0])
+			}
 			if c != nil {
 				// Go deeper
 				cn = c
@@ -170,6 +191,7 @@ func (r *Router) insert(method, path string, h HandlerFunc, t kind, ppath string
 		} else {
 			// Node already exists
 			if h != nil {
+				checkParamConflict(cn, ppath, pnames)
 				cn.addHandler(method, h)
 				cn.ppath = ppath
 				if len(cn.pnames) == 0 { // Issue #729
@@ -187,6 +209,126 @@ func (r *Router) insert(method, path string, h HandlerFunc, t kind, ppath string
 	}
 }
 
+// remove unregisters the handler for method and path, as registered with
+// `Add()`, pruning the nodes left without handlers and children so that the
+// requests they matched fall back to the broader routes, e.g. `/*`.
+func (r *Router) remove(method, path string) {
+	if path == "" {
+		return
+	}
+	if path[0] != '/' {
+		path = "/" + path
+	}
+
+	// Path as stored in the tree, params without their names
+	literals := map[int]bool{}
+	for i, l := 0, len(path); i < l; i++ {
+		if path[i] == '\\' && i+1 < l && path[i+1] == ':' {
+			path = path[:i] + path[i+1:]
+			l--
+			literals[i] = true
+		} else if path[i] == ':' {
+			j := i + 1
+			for ; i < l && path[i] != '/'; i++ {
+			}
+			path = path[:j] + path[i:]
+			i, l = j, len(path)
+		}
+	}
+
+	cn := r.tree
+	search := path
+	for {
+		if !strings.HasPrefix(search, cn.prefix) {
+			return
+		}
+		search = search[len(cn.prefix):]
+		if search == "" {
+			break
+		}
+		var c *node
+		switch {
+		case search[0] == ':' && !literals[len(path)-len(search)]:
+			c = cn.findChildByKind(pkind)
+		case search[0] == '*':
+			c = cn.findChildByKind(akind)
+		default:
+			c = cn.findChild(search[0], skind)
+		}
+		if c == nil {
+			return
+		}
+		cn = c
+	}
+
+	cn.addHandler(method, nil)
+	for cn != r.tree && !cn.hasHandler() && len(cn.children) == 0 {
+		p := cn.parent
+		for i, c := range p.children {
+			if c == cn {
+				p.children = append(p.children[:i], p.children[i+1:]...)
+				break
+			}
+		}
+		cn = p
+	}
+}
+
+// PrintRoutes writes the radix tree of the router to w for debugging, one node
+// per line indented by depth. Nodes with handlers are followed by their
+// methods, registered path and param names, e.g.
+//
+//	/users/
+//	  me [GET] /users/me
+//	  : [GET,PUT] /users/:id (id)
+func (r *Router) PrintRoutes(w io.Writer) {
+	r.tree.print(w, 0)
+}
+
+func (n *node) print(w io.Writer, depth int) {
+	fmt.Fprintf(w, "%s%s", strings.Repeat("  ", depth), n.prefix)
+	ms := []string{}
+	for _, m := range methods {
+		if n.findHandler(m) != nil {
+			ms = append(ms, m)
+		}
+	}
+	if len(ms) > 0 {
+		fmt.Fprintf(w, " [%s] %s", strings.Join(ms, ","), n.ppath)
+		if len(n.pnames) > 0 {
+			fmt.Fprintf(w, " (%s)", strings.Join(n.pnames, ", "))
+		}
+	}
+	fmt.Fprintln(w)
+	for _, c := range n.children {
+		c.print(w, depth+1)
+	}
+}
+
+// checkParamConflict panics if the node already has a route whose params are
+// named differently than the ones of the route being added, e.g. `/users/:id`
+// and `/users/:name`.
+func checkParamConflict(n *node, ppath string, pnames []string) {
+	if !n.hasHandler() {
+		return
+	}
+	for i, name := range pnames {
+		if i < len(n.pnames) && n.pnames[i] != name {
+			panic(fmt.Sprintf("akita: param ':%s' in path '%s' conflicts with param ':%s' in existing path '%s'",
+				name, ppath, n.pnames[i], n.ppath))
+		}
+	}
+}
+
+func (n *node) hasHandler() bool {
+	for _, m := range methods {
+		if n.findHandler(m) != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func newNode(t kind, pre string, p *node, c children, mh *methodHandler, ppath string, pnames []string) *node {
 	return &node{
 		kind:          t,
@@ -279,13 +421,11 @@ func (n *node) findHandler(method string) HandlerFunc {
 	}
 }
 
-func (n *node) checkMethodNotAllowed() HandlerFunc {
-	for _, m := range methods {
-		if h := n.findHandler(m); h != nil {
-			return MethodNotAllowedHandler
-		}
+func (n *node) checkMethodNotAllowed(a *Akita) HandlerFunc {
+	if n.hasHandler() {
+		return a.methodNotAllowedHandler
 	}
-	return NotFoundHandler
+	return a.notFoundHandler
 }
 
 // Find lookup a handler registered for method and path. It also parses URL for path
@@ -320,7 +460,7 @@ func (r *Router) Find(method, path string, c Context) {
 		pl := 0 // Prefix length
 		l := 0  // LCP length
 
-		if cn.label != ':' {
+		if cn.kind != pkind {
 			sl := len(search)
 			pl = len(cn.prefix)
 
@@ -416,7 +556,7 @@ End:
 
 	// NOTE: Slow zone...
 	if ctx.handler == nil {
-		ctx.handler = cn.checkMethodNotAllowed()
+		ctx.handler = cn.checkMethodNotAllowed(r.akita)
 
 		// Dig further for any, might have an empty value for *, e.g.
 		// serving a directory. Issue #207.
@@ -426,7 +566,7 @@ End:
 		if h := cn.findHandler(method); h != nil {
 			ctx.handler = h
 		} else {
-			ctx.handler = cn.checkMethodNotAllowed()
+			ctx.handler = cn.checkMethodNotAllowed(r.akita)
 		}
 		ctx.path = cn.ppath
 		ctx.pnames = cn.pnames
@@ -0,0 +1,144 @@
+package akita
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// openAPIVersion is the version of the specification generated by
+// `Akita#OpenAPI()`.
+const openAPIVersion = "3.0.3"
+
+type (
+	// byPath sorts routes by path, method and host.
+	byPath []*Route
+)
+
+// RoutesJSON returns the routes of the default router and of the hosts as a
+// JSON array, sorted by path, method and host.
+func (a *Akita) RoutesJSON() ([]byte, error) {
+	return json.Marshal(sortedRoutes(a.allRoutes()))
+}
+
+// AddRoutesEndpoint registers a GET route for path responding with
+// `Akita#RoutesJSON()`, e.g. for a debug endpoint.
+func (a *Akita) AddRoutesEndpoint(path string) *Route {
+	return a.GET(path, func(ctx Context) error {
+		b, err := a.RoutesJSON()
+		if err != nil {
+			return err
+		}
+		return ctx.JSONBlob(http.StatusOK, b)
+	})
+}
+
+// OpenAPI returns a minimal OpenAPI 3 document listing the operations of the
+// routes of the default router and of the hosts, as a skeleton for the
+// documentation of the API. The hosts aren't part of the paths, so that the
+// routes of the default router take precedence over the ones of the hosts with
+// the same method and path. The path parameters are inferred from the `:name`
+// and `*` segments, the latter being named "wildcard". Operations have a
+// default response only.
+func (a *Akita) OpenAPI(title, version string) ([]byte, error) {
+	paths := Map{}
+	for _, r := range sortedRoutes(a.allRoutes()) {
+		method := strings.ToLower(r.Method)
+		switch r.Method {
+		case DELETE, GET, HEAD, OPTIONS, PATCH, POST, PUT, TRACE:
+		default:
+			continue
+		}
+		p, params := openAPIPath(r.Path)
+		op := Map{
+			"responses": Map{
+				"default": Map{"description": "Default response"},
+			},
+		}
+		if len(params) > 0 {
+			ps := make([]Map, len(params))
+			for i, name := range params {
+				ps[i] = Map{
+					"name":     name,
+					"in":       "path",
+					"required": true,
+					"schema":   Map{"type": "string"},
+				}
+			}
+			op["parameters"] = ps
+		}
+		item, ok := paths[p].(Map)
+		if !ok {
+			item = Map{}
+			paths[p] = item
+		}
+		if _, ok := item[method]; ok && r.Host != "" {
+			continue
+		}
+		item[method] = op
+	}
+	return json.Marshal(Map{
+		"openapi": openAPIVersion,
+		"info":    Map{"title": title, "version": version},
+		"paths":   paths,
+	})
+}
+
+// AddOpenAPIEndpoint registers a GET route for path responding with
+// `Akita#OpenAPI()`.
+func (a *Akita) AddOpenAPIEndpoint(path, title, version string) *Route {
+	return a.GET(path, func(ctx Context) error {
+		b, err := a.OpenAPI(title, version)
+		if err != nil {
+			return err
+		}
+		return ctx.JSONBlob(http.StatusOK, b)
+	})
+}
+
+func (r byPath) Len() int      { return len(r) }
+func (r byPath) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byPath) Less(i, j int) bool {
+	if r[i].Path != r[j].Path {
+		return r[i].Path < r[j].Path
+	}
+	if r[i].Method != r[j].Method {
+		return r[i].Method < r[j].Method
+	}
+	return r[i].Host < r[j].Host
+}
+
+func sortedRoutes(routes []*Route) []*Route {
+	sort.Sort(byPath(routes))
+	return routes
+}
+
+// openAPIPath turns a route path into an OpenAPI path template, e.g.
+// `/users/:id` into `/users/{id}`, and returns the names of its parameters.
+func openAPIPath(path string) (string, []string) {
+	b := new(bytes.Buffer)
+	params := []string{}
+	for i, l := 0, len(path); i < l; i++ {
+		switch {
+		case path[i] == '\\' && i+1 < l && path[i+1] == ':':
+			i++
+			b.WriteByte(':')
+		case path[i] == ':':
+			j := i + 1
+			for ; j < l && path[j] != '/'; j++ {
+			}
+			name := path[i+1 : j]
+			params = append(params, name)
+			b.WriteString("{" + name + "}")
+			i = j - 1
+		case path[i] == '*':
+			params = append(params, "wildcard")
+			b.WriteString("{wildcard}")
+		default:
+			b.WriteByte(path[i])
+		}
+	}
+	return b.String(), params
+}
@@ -0,0 +1,97 @@
+package akita
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAkitaRoutesJSON(t *testing.T) {
+	a := New()
+	h := func(ctx Context) error {
+		return ctx.NoContent(http.StatusOK)
+	}
+	a.POST("/users", h).Name = "create-user"
+	a.GET("/users/:id", h).Name = "user"
+	g := a.Host("api.example.com")
+	g.DisableCatchAll()
+	g.GET("/users", h).Name = "users"
+	a.AddRoutesEndpoint("/debug/routes")
+
+	b, err := a.RoutesJSON()
+	if assert.NoError(t, err) {
+		routes := []Route{}
+		assert.NoError(t, json.Unmarshal(b, &routes))
+		assert.Equal(t, []Route{
+			{Method: GET, Path: "/debug/routes", Name: routes[0].Name},
+			{Method: GET, Path: "/users", Name: "users", Host: "api.example.com"},
+			{Method: POST, Path: "/users", Name: "create-user"},
+			{Method: GET, Path: "/users/:id", Name: "user"},
+		}, routes)
+	}
+
+	c, body := request(GET, "/debug/routes", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Contains(t, body, `{"method":"GET","path":"/users/:id","name":"user"}`)
+}
+
+func TestAkitaOpenAPI(t *testing.T) {
+	a := New()
+	h := func(ctx Context) error {
+		return ctx.NoContent(http.StatusOK)
+	}
+	a.GET("/users/:id", h)
+	a.PUT("/users/:id", h)
+	a.GET("/users/:uid/files/:fid", h)
+	a.GET("/static/*", h)
+	a.GET("/time/12\\:00", h)
+	a.CONNECT("/tunnel", h)
+	a.Host("api.example.com").GET("/accounts", h)
+	a.AddOpenAPIEndpoint("/openapi.json", "Users", "1.0.0")
+
+	b, err := a.OpenAPI("Users", "1.0.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	doc := struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name     string `json:"name"`
+				In       string `json:"in"`
+				Required bool   `json:"required"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}{}
+	if !assert.NoError(t, json.Unmarshal(b, &doc)) {
+		return
+	}
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Equal(t, "Users", doc.Info.Title)
+	assert.Equal(t, "1.0.0", doc.Info.Version)
+
+	item := doc.Paths["/users/{id}"]
+	if assert.Contains(t, item, "get") && assert.Contains(t, item, "put") {
+		params := item["get"].Parameters
+		if assert.Len(t, params, 1) {
+			assert.Equal(t, "id", params[0].Name)
+			assert.Equal(t, "path", params[0].In)
+			assert.True(t, params[0].Required)
+		}
+	}
+	assert.Len(t, doc.Paths["/users/{uid}/files/{fid}"]["get"].Parameters, 2)
+	assert.Equal(t, "wildcard", doc.Paths["/static/{wildcard}"]["get"].Parameters[0].Name)
+	assert.Contains(t, doc.Paths, "/time/12:00")
+	assert.Contains(t, doc.Paths["/accounts"], "get")
+	assert.NotContains(t, doc.Paths, "/tunnel")
+
+	c, body := request(GET, "/openapi.json", a)
+	assert.Equal(t, http.StatusOK, c)
+	assert.Contains(t, body, `"/users/{id}"`)
+}
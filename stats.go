@@ -0,0 +1,81 @@
+package akita
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Stats is a snapshot of the request statistics collected by
+	// `Akita#StatsMiddleware()`.
+	Stats struct {
+		StartTime time.Time      `json:"start_time"`
+		Uptime    int64          `json:"uptime"` // In seconds
+		Requests  uint64         `json:"requests"`
+		Statuses  map[int]uint64 `json:"statuses"`
+		BytesIn   uint64         `json:"bytes_in"`
+		BytesOut  uint64         `json:"bytes_out"`
+	}
+
+	// stats holds the counters of `Akita#StatsMiddleware()`. The 64-bit
+	// fields accessed atomically come first for alignment.
+	stats struct {
+		requests uint64
+		bytesIn  uint64
+		bytesOut uint64
+		statuses [600]uint64
+		start    time.Time
+	}
+)
+
+// StatsMiddleware returns a middleware counting the requests, their status
+// codes and the bytes read and written, available as `Akita#Stats()`. It is
+// typically added first with `Use()`, so the status codes are the ones sent.
+func (a *Akita) StatsMiddleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) (err error) {
+			if err = next(ctx); err != nil {
+				ctx.Error(err)
+			}
+			req := ctx.Request()
+			res := ctx.Response()
+			atomic.AddUint64(&a.stats.requests, 1)
+			if req.ContentLength > 0 {
+				atomic.AddUint64(&a.stats.bytesIn, uint64(req.ContentLength))
+			}
+			atomic.AddUint64(&a.stats.bytesOut, uint64(res.Size))
+			if res.Status > 0 && res.Status < len(a.stats.statuses) {
+				atomic.AddUint64(&a.stats.statuses[res.Status], 1)
+			}
+			return
+		}
+	}
+}
+
+// Stats returns a snapshot of the statistics collected by
+// `Akita#StatsMiddleware()` since the instance was created.
+func (a *Akita) Stats() *Stats {
+	s := &Stats{
+		StartTime: a.stats.start,
+		Uptime:    int64(time.Since(a.stats.start) / time.Second),
+		Requests:  atomic.LoadUint64(&a.stats.requests),
+		Statuses:  map[int]uint64{},
+		BytesIn:   atomic.LoadUint64(&a.stats.bytesIn),
+		BytesOut:  atomic.LoadUint64(&a.stats.bytesOut),
+	}
+	for code := range a.stats.statuses {
+		if n := atomic.LoadUint64(&a.stats.statuses[code]); n > 0 {
+			s.Statuses[code] = n
+		}
+	}
+	return s
+}
+
+// AddStatsEndpoint registers a GET route for path responding with
+// `Akita#Stats()` as JSON, e.g. `/debug/stats`.
+func (a *Akita) AddStatsEndpoint(path string) *Route {
+	return a.GET(path, func(ctx Context) error {
+		return ctx.JSON(http.StatusOK, a.Stats())
+	})
+}
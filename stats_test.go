@@ -0,0 +1,54 @@
+package akita
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAkitaStats(t *testing.T) {
+	a := New()
+	a.Use(a.StatsMiddleware())
+	a.POST("/users", func(ctx Context) error {
+		return ctx.String(http.StatusCreated, "OK")
+	})
+	a.GET("/error", func(ctx Context) error {
+		return errors.New("error")
+	})
+	a.AddStatsEndpoint("/debug/stats")
+
+	s := a.Stats()
+	assert.Equal(t, uint64(0), s.Requests)
+	assert.Empty(t, s.Statuses)
+
+	req := httptest.NewRequest(POST, "/users", strings.NewReader("name=Jon"))
+	a.ServeHTTP(httptest.NewRecorder(), req)
+	request(GET, "/error", a)
+	request(GET, "/missing", a)
+	request(GET, "/missing", a)
+
+	s = a.Stats()
+	assert.Equal(t, uint64(4), s.Requests)
+	assert.Equal(t, map[int]uint64{
+		http.StatusCreated:             1,
+		http.StatusInternalServerError: 1,
+		http.StatusNotFound:            2,
+	}, s.Statuses)
+	assert.Equal(t, uint64(8), s.BytesIn)
+	assert.True(t, s.BytesOut > 2)
+	assert.False(t, s.StartTime.IsZero())
+
+	code, body := request(GET, "/debug/stats", a)
+	assert.Equal(t, http.StatusOK, code)
+	snapshot := new(Stats)
+	if assert.NoError(t, json.Unmarshal([]byte(body), snapshot)) {
+		assert.Equal(t, uint64(4), snapshot.Requests)
+		assert.Equal(t, uint64(2), snapshot.Statuses[http.StatusNotFound])
+	}
+	assert.Equal(t, uint64(5), a.Stats().Requests)
+}
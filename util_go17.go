@@ -3,10 +3,23 @@
 package akita
 
 import (
+	"encoding/json"
 	"net/url"
+	"strings"
 )
 
 // PathUnescape is wraps `url.QueryUnescape`
 func PathUnescape(s string) (string, error) {
 	return url.QueryUnescape(s)
 }
+
+// pathEscape escapes s as a path segment, slashes included
+func pathEscape(s string) string {
+	return strings.Replace((&url.URL{Path: s}).EscapedPath(), "/", "%2F", -1)
+}
+
+// unmarshalTypeErrorField returns "", `json.UnmarshalTypeError` having no
+// field before Go 1.8
+func unmarshalTypeErrorField(ute *json.UnmarshalTypeError) string {
+	return ""
+}
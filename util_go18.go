@@ -2,9 +2,22 @@
 
 package akita
 
-import "net/url"
+import (
+	"encoding/json"
+	"net/url"
+)
 
 // PathUnescape is wraps `url.PathUnescape`
 func PathUnescape(s string) (string, error) {
 	return url.PathUnescape(s)
 }
+
+// pathEscape is wraps `url.PathEscape`
+func pathEscape(s string) string {
+	return url.PathEscape(s)
+}
+
+// unmarshalTypeErrorField returns the field of ute
+func unmarshalTypeErrorField(ute *json.UnmarshalTypeError) string {
+	return ute.Field
+}
@@ -0,0 +1,110 @@
+// Package validator provides an `akita.Validator` backed by
+// go-playground/validator. It lives in its own package so applications which
+// don't validate requests don't pull in the dependency.
+//
+// Example:
+//
+//	a := akita.New()
+//	a.Validator = validator.New()
+//
+//	type User struct {
+//	  Email string `json:"email" validate:"required,email"`
+//	}
+//
+//	u := new(User)
+//	if err := ctx.Bind(u); err != nil {
+//	  return err
+//	}
+//	if err := ctx.Validate(u); err != nil {
+//	  return err // 400 with field-level messages
+//	}
+package validator
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	playground "github.com/go-playground/validator/v10"
+	"github.com/itchenyi/akita"
+)
+
+type (
+	// DefaultValidator is an `akita.Validator` wrapping go-playground/validator.
+	DefaultValidator struct {
+		validate *playground.Validate
+	}
+
+	// FieldError describes a single field which failed validation.
+	FieldError struct {
+		Field   string `json:"field"`
+		Tag     string `json:"tag"`
+		Message string `json:"message"`
+	}
+)
+
+// New returns a DefaultValidator. Fields are reported by their `json` tag name
+// when one is set, so errors match the request body.
+func New() *DefaultValidator {
+	v := playground.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return &DefaultValidator{validate: v}
+}
+
+// Engine returns the underlying go-playground validator, e.g. to register
+// custom validations.
+func (v *DefaultValidator) Engine() *playground.Validate {
+	return v.validate
+}
+
+// Validate implements `akita.Validator`. Validation failures are returned as an
+// `*akita.HTTPError` with status 400 whose message holds the failed fields.
+func (v *DefaultValidator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	if err == nil {
+		return nil
+	}
+	ve, ok := err.(playground.ValidationErrors)
+	if !ok {
+		return err
+	}
+	fields := make([]FieldError, len(ve))
+	for i, fe := range ve {
+		fields[i] = FieldError{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Message: fieldMessage(fe),
+		}
+	}
+	he := akita.NewHTTPError(http.StatusBadRequest, akita.Map{
+		"message": "Validation failed",
+		"errors":  fields,
+	})
+	he.Inner = err
+	return he
+}
+
+func fieldMessage(fe playground.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("field '%s' is required", fe.Field())
+	case "email":
+		return fmt.Sprintf("field '%s' must be a valid email address", fe.Field())
+	case "min":
+		return fmt.Sprintf("field '%s' must be at least %s", fe.Field(), fe.Param())
+	case "max":
+		return fmt.Sprintf("field '%s' must be at most %s", fe.Field(), fe.Param())
+	case "len":
+		return fmt.Sprintf("field '%s' must have length %s", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("field '%s' must be one of [%s]", fe.Field(), fe.Param())
+	}
+	return fmt.Sprintf("field '%s' failed on the '%s' validation", fe.Field(), fe.Tag())
+}
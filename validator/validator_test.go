@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/itchenyi/akita"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	Name  string `json:"name"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+func TestValidatorValid(t *testing.T) {
+	v := New()
+	assert.NoError(t, v.Validate(&user{Email: "jon@labstack.com"}))
+}
+
+func TestValidatorInvalid(t *testing.T) {
+	v := New()
+
+	err := v.Validate(&user{})
+	if assert.IsType(t, new(akita.HTTPError), err) {
+		he := err.(*akita.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+		m := he.Message.(akita.Map)
+		fields := m["errors"].([]FieldError)
+		if assert.Len(t, fields, 1) {
+			assert.Equal(t, "email", fields[0].Field)
+			assert.Equal(t, "required", fields[0].Tag)
+			assert.Equal(t, "field 'email' is required", fields[0].Message)
+		}
+	}
+
+	err = v.Validate(&user{Email: "jon"})
+	if assert.IsType(t, new(akita.HTTPError), err) {
+		fields := err.(*akita.HTTPError).Message.(akita.Map)["errors"].([]FieldError)
+		if assert.Len(t, fields, 1) {
+			assert.Equal(t, "email", fields[0].Tag)
+			assert.Equal(t, "field 'email' must be a valid email address", fields[0].Message)
+		}
+	}
+}
+
+func TestValidatorContext(t *testing.T) {
+	a := akita.New()
+	a.Validator = New()
+	a.POST("/users", func(ctx akita.Context) error {
+		u := new(user)
+		if err := ctx.Bind(u); err != nil {
+			return err
+		}
+		if err := ctx.Validate(u); err != nil {
+			return err
+		}
+		return ctx.JSON(http.StatusCreated, u)
+	})
+
+	req := httptest.NewRequest(akita.POST, "/users", strings.NewReader(`{"name":"Jon","email":"nope"}`))
+	req.Header.Set(akita.HeaderContentType, akita.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"email"`)
+
+	req = httptest.NewRequest(akita.POST, "/users", strings.NewReader(`{"name":"Jon","email":"jon@labstack.com"}`))
+	req.Header.Set(akita.HeaderContentType, akita.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}